@@ -22,9 +22,15 @@ func (a *App) getActionDefinition(resource, action string) (*ActionDefinition, b
 }
 
 func (rd *ResourceDefinition) AddActionDefinition(ad ActionDefinition) {
+	if err := rd.addAction(ad); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) addAction(ad ActionDefinition) error {
 	for _, existing := range rd.actions {
 		if existing.Name == ad.Name {
-			panic(fmt.Sprintf("ActionDefinition with the same name '%s' already exists", ad.Name))
+			return fmt.Errorf("ActionDefinition with the same name '%s' already exists", ad.Name)
 		}
 	}
 
@@ -37,6 +43,7 @@ func (rd *ResourceDefinition) AddActionDefinition(ad ActionDefinition) {
 	}
 
 	rd.actions = append(rd.actions, ad)
+	return nil
 }
 
 type ActionDefinition struct {
@@ -52,6 +59,10 @@ type ActionDefinition struct {
 	OutputSchema *JSONSchema
 	// Handler is the function that will be called when the action is invoked.
 	Handler func(context.Context, *ActionRequest) (*ActionResponse, error)
+	// Validate is an optional function that checks the input for rules that JSON schema can't express.
+	// It is called after schema validation and default injection, and before the Handler.
+	// Return FieldViolations to reject the input with InvalidArgument.
+	Validate func(context.Context, *ActionRequest) error
 }
 
 // ActionRequest contains the input data for an operation on a resource.
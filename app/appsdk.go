@@ -11,6 +11,8 @@ type App struct {
 	appv1connect.UnimplementedAppServiceHandler
 
 	resourceDefinitions []ResourceDefinition
+	coerceTypes         bool
+	valueOptions        ValueOptions
 }
 
 func New(opts ...AppOption) *App {
@@ -19,8 +21,20 @@ func New(opts ...AppOption) *App {
 		opt(&options)
 	}
 
+	if len(options.schemaOptions) > 0 {
+		for i, rd := range options.resourceDefinitions {
+			compiled, err := rd.recompileSchemas(options.schemaOptions)
+			if err != nil {
+				panic(fmt.Sprintf("resource type '%s': %v", rd.Type, err))
+			}
+			options.resourceDefinitions[i] = compiled
+		}
+	}
+
 	return &App{
 		resourceDefinitions: options.resourceDefinitions,
+		coerceTypes:         options.coerceTypes,
+		valueOptions:        options.valueOptions,
 	}
 }
 
@@ -28,6 +42,9 @@ type AppOption func(*appOptions)
 
 type appOptions struct {
 	resourceDefinitions []ResourceDefinition
+	coerceTypes         bool
+	schemaOptions       []SchemaOption
+	valueOptions        ValueOptions
 }
 
 const ResourceTypePattern = `^[A-Za-z_][A-Za-z0-9_]*$`
@@ -57,3 +74,28 @@ func WithResourceDefinitions(rds ...ResourceDefinition) AppOption {
 		}
 	}
 }
+
+// WithTypeCoercion enables the coercion of operation and action inputs to the types required
+// by their input schema before validation. For example, the string "5" is converted to the
+// number 5 when the schema expects an integer, and "true" to a boolean.
+func WithTypeCoercion() AppOption {
+	return func(o *appOptions) {
+		o.coerceTypes = true
+	}
+}
+
+// WithSchemaOptions compiles the schemas of every ResourceDefinition with the custom formats and
+// keywords of the options, in addition to the options the schemas were parsed with.
+func WithSchemaOptions(opts ...SchemaOption) AppOption {
+	return func(o *appOptions) {
+		o.schemaOptions = append(o.schemaOptions, opts...)
+	}
+}
+
+// WithValueOptions configures how the Go values returned by handlers in Resource.Properties and
+// ActionResponse.Output are normalized to JSON values. See NormalizeValue.
+func WithValueOptions(opts ValueOptions) AppOption {
+	return func(o *appOptions) {
+		o.valueOptions = opts
+	}
+}
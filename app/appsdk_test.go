@@ -56,6 +56,24 @@ func TestNew(t *testing.T) {
 				),
 			},
 		},
+		{
+			desc: "OK - With Type Coercion",
+			app: &App{
+				coerceTypes: true,
+			},
+			options: []AppOption{
+				WithTypeCoercion(),
+			},
+		},
+		{
+			desc: "OK - With Value Options",
+			app: &App{
+				valueOptions: ValueOptions{BigNumbers: BigNumberAsString},
+			},
+			options: []AppOption{
+				WithValueOptions(ValueOptions{BigNumbers: BigNumberAsString}),
+			},
+		},
 		{
 			desc:        "PANIC - With bad Resource Type",
 			shouldPanic: true,
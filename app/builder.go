@@ -0,0 +1,376 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ResourceBuilder builds a ResourceDefinition without panicking on misconfiguration.
+//
+// Unlike the CreateFn, UpdateFn, ReadFn, DeleteFn and ListFn methods, the builder does not
+// depend on the order in which it is configured, and every configuration problem is collected
+// and returned together by Build. This is useful for apps that load their definitions dynamically
+// and want to report all issues at once instead of crashing on the first one.
+//
+//	rd, err := app.NewResource("bucket").
+//		DisplayName("Bucket").
+//		Properties(propertiesSchema).
+//		Create(createFn, createSchema).
+//		Read(readFn).
+//		Build()
+type ResourceBuilder struct {
+	rd ResourceDefinition
+
+	create         *pendingOperation
+	update         *pendingOperation
+	createValidate ValidateFunc
+	updateValidate ValidateFunc
+	read           OperationFunc
+	delete         OperationFunc
+	list           ListFunc
+	healthcheck    HealthCheckFunc
+	actions        []ActionDefinition
+	migrations     []migration
+	options        map[string]OptionsFunc
+
+	errs []error
+}
+
+// pendingOperation holds an operation that will be added to the ResourceDefinition on Build.
+type pendingOperation struct {
+	fn          OperationFunc
+	inputSchema *JSONSchema
+}
+
+// NewResource returns a ResourceBuilder for a ResourceDefinition of the given type.
+func NewResource(resourceType string) *ResourceBuilder {
+	return &ResourceBuilder{
+		rd: ResourceDefinition{
+			Type: resourceType,
+		},
+	}
+}
+
+// DisplayName sets the display name of the resource type.
+func (b *ResourceBuilder) DisplayName(name string) *ResourceBuilder {
+	b.rd.DisplayName = name
+	return b
+}
+
+// Description sets the description of the resource type.
+func (b *ResourceBuilder) Description(description string) *ResourceBuilder {
+	b.rd.Description = description
+	return b
+}
+
+// LifecycleStage sets the LifecycleStage of the resource type.
+func (b *ResourceBuilder) LifecycleStage(stage LifecycleStage) *ResourceBuilder {
+	b.rd.LifecycleStage = stage
+	return b
+}
+
+// Link adds a documentation or support link to the resource type.
+func (b *ResourceBuilder) Link(l Link) *ResourceBuilder {
+	b.rd.Links = append(b.rd.Links, l)
+	return b
+}
+
+// LinkTemplate adds a resource-specific link template to the resource type.
+func (b *ResourceBuilder) LinkTemplate(lt LinkTemplate) *ResourceBuilder {
+	b.rd.LinkTemplates = append(b.rd.LinkTemplates, lt)
+	return b
+}
+
+// Instructions sets the Markdown formatted instructions for the resource type.
+func (b *ResourceBuilder) Instructions(markdown string) *ResourceBuilder {
+	b.rd.InstructionsMarkdown = markdown
+	return b
+}
+
+// Properties sets the parsed JSON schema for the resource Properties.
+func (b *ResourceBuilder) Properties(s *JSONSchema) *ResourceBuilder {
+	if s == nil {
+		b.errs = append(b.errs, errors.New("properties schema is nil"))
+		return b
+	}
+
+	b.rd.PropertiesSchema = s
+	return b
+}
+
+// PropertiesJSON parses the JSON schema and sets it as the schema for the resource Properties.
+// Parsing errors are returned by Build.
+func (b *ResourceBuilder) PropertiesJSON(raw []byte) *ResourceBuilder {
+	s, err := ParseJSONSchema(raw)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("properties schema: %w", err))
+		return b
+	}
+
+	return b.Properties(s)
+}
+
+// Create adds a Create operation Handler. See ResourceDefinition.CreateFn.
+func (b *ResourceBuilder) Create(fn OperationFunc, inputSchema *JSONSchema) *ResourceBuilder {
+	if b.create != nil {
+		b.errs = append(b.errs, errors.New("create: operation is already set"))
+		return b
+	}
+
+	b.create = &pendingOperation{fn: fn, inputSchema: inputSchema}
+	return b
+}
+
+// CreateJSON parses the input JSON schema and adds a Create operation Handler.
+// Parsing errors are returned by Build.
+func (b *ResourceBuilder) CreateJSON(fn OperationFunc, rawInputSchema []byte) *ResourceBuilder {
+	s, err := ParseJSONSchema(rawInputSchema)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("create: input schema: %w", err))
+		return b
+	}
+
+	return b.Create(fn, s)
+}
+
+// Update adds an Update operation Handler. See ResourceDefinition.UpdateFn.
+func (b *ResourceBuilder) Update(fn OperationFunc, inputSchema *JSONSchema) *ResourceBuilder {
+	if b.update != nil {
+		b.errs = append(b.errs, errors.New("update: operation is already set"))
+		return b
+	}
+
+	b.update = &pendingOperation{fn: fn, inputSchema: inputSchema}
+	return b
+}
+
+// UpdateJSON parses the input JSON schema and adds an Update operation Handler.
+// Parsing errors are returned by Build.
+func (b *ResourceBuilder) UpdateJSON(fn OperationFunc, rawInputSchema []byte) *ResourceBuilder {
+	s, err := ParseJSONSchema(rawInputSchema)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("update: input schema: %w", err))
+		return b
+	}
+
+	return b.Update(fn, s)
+}
+
+// CreateValidate adds a ValidateFunc to the Create operation. See ResourceDefinition.CreateValidateFn.
+// It can be called before or after Create, but Build returns an error if no Create operation is set.
+func (b *ResourceBuilder) CreateValidate(fn ValidateFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("create: ValidateFunc must be set for a Create Operation"))
+		return b
+	}
+	if b.createValidate != nil {
+		b.errs = append(b.errs, errors.New("create: ValidateFunc is already set"))
+		return b
+	}
+
+	b.createValidate = fn
+	return b
+}
+
+// UpdateValidate adds a ValidateFunc to the Update operation. See ResourceDefinition.UpdateValidateFn.
+// It can be called before or after Update, but Build returns an error if no Update operation is set.
+func (b *ResourceBuilder) UpdateValidate(fn ValidateFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("update: ValidateFunc must be set for an Update Operation"))
+		return b
+	}
+	if b.updateValidate != nil {
+		b.errs = append(b.errs, errors.New("update: ValidateFunc is already set"))
+		return b
+	}
+
+	b.updateValidate = fn
+	return b
+}
+
+// Read adds a Read operation Handler. See ResourceDefinition.ReadFn.
+func (b *ResourceBuilder) Read(fn OperationFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("read: OperationFunc must be set for a Read Operation"))
+		return b
+	}
+	if b.read != nil {
+		b.errs = append(b.errs, errors.New("read: operation is already set"))
+		return b
+	}
+
+	b.read = fn
+	return b
+}
+
+// Delete adds a Delete operation Handler. See ResourceDefinition.DeleteFn.
+func (b *ResourceBuilder) Delete(fn OperationFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("delete: OperationFunc must be set for a Delete Operation"))
+		return b
+	}
+	if b.delete != nil {
+		b.errs = append(b.errs, errors.New("delete: operation is already set"))
+		return b
+	}
+
+	b.delete = fn
+	return b
+}
+
+// List adds a List Handler. See ResourceDefinition.ListFn.
+func (b *ResourceBuilder) List(fn ListFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("list: ListFunc must be set for a List Operation"))
+		return b
+	}
+	if b.list != nil {
+		b.errs = append(b.errs, errors.New("list: operation is already set"))
+		return b
+	}
+
+	b.list = fn
+	return b
+}
+
+// HealthCheck adds a HealthCheck Handler. See ResourceDefinition.HealthCheckFn.
+func (b *ResourceBuilder) HealthCheck(fn HealthCheckFunc) *ResourceBuilder {
+	if fn == nil {
+		b.errs = append(b.errs, errors.New("healthcheck: HealthCheckFunc must be set for a HealthCheck Operation"))
+		return b
+	}
+	if b.healthcheck != nil {
+		b.errs = append(b.errs, errors.New("healthcheck: operation is already set"))
+		return b
+	}
+
+	b.healthcheck = fn
+	return b
+}
+
+// Action adds an ActionDefinition. See ResourceDefinition.AddActionDefinition.
+func (b *ResourceBuilder) Action(ad ActionDefinition) *ResourceBuilder {
+	b.actions = append(b.actions, ad)
+	return b
+}
+
+// SchemaVersion sets the current version of the PropertiesSchema.
+func (b *ResourceBuilder) SchemaVersion(version int) *ResourceBuilder {
+	b.rd.SchemaVersion = version
+	return b
+}
+
+// Migration adds a MigrationFunc that upgrades resource properties from the fromVersion
+// schema version to fromVersion+1. See ResourceDefinition.AddMigration.
+func (b *ResourceBuilder) Migration(fromVersion int, fn MigrationFunc) *ResourceBuilder {
+	b.migrations = append(b.migrations, migration{from: fromVersion, fn: fn})
+	return b
+}
+
+// Options adds an OptionsFunc for an input property. See ResourceDefinition.AddOptionsFunc.
+func (b *ResourceBuilder) Options(property string, fn OptionsFunc) *ResourceBuilder {
+	if _, ok := b.options[property]; ok {
+		b.errs = append(b.errs, fmt.Errorf("options: OptionsFunc for property '%s' already exists", property))
+		return b
+	}
+
+	if b.options == nil {
+		b.options = make(map[string]OptionsFunc)
+	}
+	b.options[property] = fn
+	return b
+}
+
+// Build returns the configured ResourceDefinition.
+// All configuration problems are joined together in the returned error.
+func (b *ResourceBuilder) Build() (ResourceDefinition, error) {
+	errs := append([]error(nil), b.errs...)
+	rd := b.rd
+	rd.Links = slices.Clone(b.rd.Links)
+	rd.LinkTemplates = slices.Clone(b.rd.LinkTemplates)
+
+	if !resourceTypeRegex.MatchString(rd.Type) {
+		errs = append(errs, fmt.Errorf("resource type '%s' does not match pattern %s", rd.Type, ResourceTypePattern))
+	}
+
+	if b.create != nil {
+		if err := rd.setCreate(b.create.fn, b.create.inputSchema); err != nil {
+			errs = append(errs, fmt.Errorf("create: %w", err))
+		}
+	}
+	if b.createValidate != nil {
+		if b.create == nil {
+			errs = append(errs, errors.New("create: Create operation must be set to add a ValidateFunc"))
+		} else if rd.create != nil {
+			rd.create.validate = b.createValidate
+		}
+	}
+
+	if b.update != nil {
+		if err := rd.setUpdate(b.update.fn, b.update.inputSchema); err != nil {
+			errs = append(errs, fmt.Errorf("update: %w", err))
+		}
+	}
+	if b.updateValidate != nil {
+		if b.update == nil {
+			errs = append(errs, errors.New("update: Update operation must be set to add a ValidateFunc"))
+		} else if rd.update != nil {
+			rd.update.validate = b.updateValidate
+		}
+	}
+
+	if b.read != nil {
+		if err := rd.setRead(b.read); err != nil {
+			errs = append(errs, fmt.Errorf("read: %w", err))
+		}
+	}
+
+	if b.delete != nil {
+		if err := rd.setDelete(b.delete); err != nil {
+			errs = append(errs, fmt.Errorf("delete: %w", err))
+		}
+	}
+
+	if b.list != nil {
+		if err := rd.setList(b.list); err != nil {
+			errs = append(errs, fmt.Errorf("list: %w", err))
+		}
+	}
+
+	rd.healthcheck = b.healthcheck
+
+	for _, ad := range b.actions {
+		if ad.Name == "" {
+			errs = append(errs, errors.New("action: name is required"))
+			continue
+		}
+
+		if ad.Handler == nil {
+			errs = append(errs, fmt.Errorf("action %s: Handler must be set", ad.Name))
+		}
+
+		if err := rd.addAction(ad); err != nil {
+			errs = append(errs, fmt.Errorf("action %s: %w", ad.Name, err))
+		}
+	}
+
+	for _, m := range b.migrations {
+		if err := rd.addMigration(m.from, m.fn); err != nil {
+			errs = append(errs, fmt.Errorf("migration: %w", err))
+		}
+	}
+
+	for _, property := range slices.Sorted(maps.Keys(b.options)) {
+		if err := rd.addOptionsFunc(property, b.options[property]); err != nil {
+			errs = append(errs, fmt.Errorf("options: %w", err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return ResourceDefinition{}, err
+	}
+
+	return rd, nil
+}
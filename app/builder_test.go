@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceBuilder(t *testing.T) {
+	parsedSchema := MustParseJSONSchema(GenericEmptySchema)
+	simpleActionFn := func(_ context.Context, _ *ActionRequest) (*ActionResponse, error) {
+		return &ActionResponse{}, nil
+	}
+
+	testCases := []struct {
+		desc    string
+		builder *ResourceBuilder
+		errs    []string
+	}{
+		{
+			desc: "OK - Fully Loaded",
+			builder: NewResource("example").
+				DisplayName("Example").
+				Description("An example resource").
+				LifecycleStage(LifecycleStageOperate).
+				Link(Link{URL: "http://example.com", Title: "Example", Type: LinkTypeDocumentation}).
+				Instructions("This is an example resource").
+				Properties(parsedSchema).
+				Create(simpleOpFn, parsedSchema).
+				Update(simpleOpFn, parsedSchema).
+				Read(simpleOpFn).
+				Delete(simpleOpFn).
+				List(simpleListFn).
+				HealthCheck(simpleHealthcheckFn).
+				Action(ActionDefinition{Name: "do_something", Handler: simpleActionFn}),
+		},
+		{
+			desc: "OK - Operations before Properties",
+			builder: NewResource("example").
+				Create(simpleOpFn, parsedSchema).
+				Read(simpleOpFn).
+				Properties(parsedSchema),
+		},
+		{
+			desc: "OK - JSON schemas",
+			builder: NewResource("example").
+				PropertiesJSON(GenericEmptySchema).
+				CreateJSON(simpleOpFn, GenericEmptySchema).
+				UpdateJSON(simpleOpFn, GenericEmptySchema),
+		},
+		{
+			desc: "OK - Validators before operations",
+			builder: NewResource("example").
+				Properties(parsedSchema).
+				CreateValidate(simpleValidateFn).
+				UpdateValidate(simpleValidateFn).
+				Create(simpleOpFn, parsedSchema).
+				Update(simpleOpFn, parsedSchema),
+		},
+		{
+			desc: "ERR - Validators",
+			builder: NewResource("example").
+				Properties(parsedSchema).
+				CreateValidate(nil).
+				UpdateValidate(simpleValidateFn).
+				UpdateValidate(simpleValidateFn),
+			errs: []string{
+				"create: ValidateFunc must be set for a Create Operation",
+				"update: ValidateFunc is already set",
+				"update: Update operation must be set to add a ValidateFunc",
+			},
+		},
+		{
+			desc: "ERR - Accumulates all errors",
+			builder: NewResource("&--invalid").
+				CreateJSON(simpleOpFn, invalidSchema).
+				Update(simpleOpFn, nil).
+				Read(nil).
+				List(simpleListFn).
+				Action(ActionDefinition{Name: "do_something", Handler: simpleActionFn}).
+				Action(ActionDefinition{Name: "do_something", Handler: simpleActionFn}).
+				Action(ActionDefinition{Name: "no_handler"}),
+			errs: []string{
+				"create: input schema: un marshall schema: invalid character '}' looking for beginning of value",
+				"read: OperationFunc must be set for a Read Operation",
+				"resource type '&--invalid' does not match pattern ^[A-Za-z_][A-Za-z0-9_]*$",
+				"update: input schema is nil",
+				"list: properties schema must be set before adding a List handler",
+				"action do_something: ActionDefinition with the same name 'do_something' already exists",
+				"action no_handler: Handler must be set",
+			},
+		},
+		{
+			desc: "ERR - Duplicate operation",
+			builder: NewResource("example").
+				Properties(parsedSchema).
+				Create(simpleOpFn, parsedSchema).
+				Create(simpleOpFn, parsedSchema),
+			errs: []string{"create: operation is already set"},
+		},
+		{
+			desc: "ERR - Duplicate operations are all reported",
+			builder: NewResource("example").
+				Properties(parsedSchema).
+				Read(simpleOpFn).
+				Read(simpleOpFn).
+				Delete(simpleOpFn).
+				Delete(simpleOpFn).
+				List(simpleListFn).
+				List(simpleListFn).
+				HealthCheck(simpleHealthcheckFn).
+				HealthCheck(simpleHealthcheckFn).
+				Update(simpleOpFn, parsedSchema).
+				Update(simpleOpFn, parsedSchema),
+			errs: []string{
+				"read: operation is already set",
+				"delete: operation is already set",
+				"list: operation is already set",
+				"healthcheck: operation is already set",
+				"update: operation is already set",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rd, err := tc.builder.Build()
+			if len(tc.errs) > 0 {
+				require.Error(t, err)
+				for _, e := range tc.errs {
+					assert.ErrorContains(t, err, e)
+				}
+				assert.Equal(t, ResourceDefinition{}, rd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "example", rd.Type)
+			assert.NotNil(t, rd.PropertiesSchema)
+		})
+	}
+}
+
+func TestResourceBuilderValidators(t *testing.T) {
+	parsedSchema := MustParseJSONSchema(GenericEmptySchema)
+
+	rd, err := NewResource("example").
+		Properties(parsedSchema).
+		UpdateValidate(simpleValidateFn).
+		Create(simpleOpFn, parsedSchema).
+		Update(simpleOpFn, parsedSchema).
+		CreateValidate(simpleValidateFn).
+		Build()
+	require.NoError(t, err)
+	assert.NotNil(t, rd.create.validate)
+	assert.NotNil(t, rd.update.validate)
+}
+
+func TestResourceBuilderClonesLinks(t *testing.T) {
+	b := NewResource("example").
+		Link(Link{URL: "https://example.com/docs", Title: "Docs"}).
+		LinkTemplate(LinkTemplate{URL: "https://example.com/{{ resource.name }}", Title: "Console"})
+
+	rd, err := b.Build()
+	require.NoError(t, err)
+
+	b.Link(Link{URL: "https://example.com/support", Title: "Support"})
+	rd.Links[0].Title = "Changed"
+	rd.LinkTemplates[0].Title = "Changed"
+
+	again, err := b.Build()
+	require.NoError(t, err)
+	assert.Len(t, rd.Links, 1)
+	assert.Equal(t, "Docs", again.Links[0].Title)
+	assert.Equal(t, "Console", again.LinkTemplates[0].Title)
+}
+
+func TestResourceBuilderMatchesSetters(t *testing.T) {
+	want := generateRD([]string{"create", "read", "update", "delete", "list", "healthcheck"})
+
+	got, err := NewResource(want.Type).
+		DisplayName(want.DisplayName).
+		Description(want.Description).
+		LifecycleStage(want.LifecycleStage).
+		Link(want.Links[0]).
+		Instructions(want.InstructionsMarkdown).
+		Properties(want.PropertiesSchema).
+		Create(simpleOpFn, want.create.schema.input).
+		Update(simpleOpFn, want.update.schema.input).
+		Read(simpleOpFn).
+		Delete(simpleOpFn).
+		List(simpleListFn).
+		HealthCheck(simpleHealthcheckFn).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, want.DisplayName, got.DisplayName)
+	assert.Equal(t, want.Links, got.Links)
+	assert.Equal(t, want.create.schema, got.create.schema)
+	assert.Equal(t, want.update.schema, got.update.schema)
+	assert.Equal(t, want.read.schema, got.read.schema)
+	assert.Equal(t, want.delete.schema, got.delete.schema)
+	assert.Equal(t, want.list.schema, got.list.schema)
+	assert.NotNil(t, got.healthcheck)
+}
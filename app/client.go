@@ -0,0 +1,355 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"connectrpc.com/connect"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Client calls the AppService of a Tempest app with the SDK's types.
+// Errors returned by the app are *connect.Error values, so the code can be checked with connect.CodeOf.
+type Client struct {
+	service appv1connect.AppServiceClient
+}
+
+// NewClient returns a Client for the app served at baseURL, for example "http://localhost:8080".
+func NewClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) *Client {
+	return NewServiceClient(appv1connect.NewAppServiceClient(httpClient, baseURL, opts...))
+}
+
+// NewServiceClient returns a Client that calls the AppServiceClient.
+// An *App is an AppServiceClient, so NewServiceClient(a) calls the App in-process.
+func NewServiceClient(service appv1connect.AppServiceClient) *Client {
+	return &Client{service: service}
+}
+
+// ResourceDescriptor describes a ResourceDefinition of an app, as returned by Client.Describe.
+type ResourceDescriptor struct {
+	Type                 string
+	DisplayName          string
+	Description          string
+	LifecycleStage       LifecycleStage
+	Links                []Link
+	InstructionsMarkdown string
+
+	// PropertiesSchema, CreateInputSchema and UpdateInputSchema are nil when the app does not describe them.
+	PropertiesSchema  *JSONSchema
+	CreateInputSchema *JSONSchema
+	UpdateInputSchema *JSONSchema
+
+	CreateSupported      bool
+	ReadSupported        bool
+	UpdateSupported      bool
+	DeleteSupported      bool
+	ListSupported        bool
+	HealthCheckSupported bool
+
+	Actions []ActionDescriptor
+}
+
+// ActionDescriptor describes an ActionDefinition of an app.
+type ActionDescriptor struct {
+	Name         string
+	DisplayName  string
+	Description  string
+	InputSchema  *JSONSchema
+	OutputSchema *JSONSchema
+}
+
+// Describe returns the ResourceDefinitions of the app. The schemas are compiled with the options,
+// which are needed when they use custom formats or keywords.
+func (c *Client) Describe(ctx context.Context, opts ...SchemaOption) ([]*ResourceDescriptor, error) {
+	res, err := c.service.Describe(ctx, connect.NewRequest(&appv1.DescribeRequest{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return DescriptorsFromProto(res.Msg, opts...)
+}
+
+// DescriptorsFromProto converts the ResourceDefinitions of a DescribeResponse, for example one
+// stored by a previous call, as Client.Describe does.
+func DescriptorsFromProto(res *appv1.DescribeResponse, opts ...SchemaOption) ([]*ResourceDescriptor, error) {
+	descriptors := make([]*ResourceDescriptor, 0, len(res.GetResourceDefinitions()))
+	for _, rd := range res.GetResourceDefinitions() {
+		d, err := resourceDescriptorFromProto(rd, opts)
+		if err != nil {
+			return nil, fmt.Errorf("resource type %s: %w", rd.Type, err)
+		}
+		descriptors = append(descriptors, d)
+	}
+
+	return descriptors, nil
+}
+
+func resourceDescriptorFromProto(rd *appv1.ResourceDefinition, opts []SchemaOption) (*ResourceDescriptor, error) {
+	d := &ResourceDescriptor{
+		Type:                 rd.GetType(),
+		DisplayName:          rd.GetDisplayName(),
+		Description:          rd.GetDescription(),
+		LifecycleStage:       LifecycleStage(rd.GetLifecycleStage()),
+		InstructionsMarkdown: rd.GetInstructionsMarkdown(),
+		CreateSupported:      rd.GetCreateSupported(),
+		ReadSupported:        rd.GetReadSupported(),
+		UpdateSupported:      rd.GetUpdateSupported(),
+		DeleteSupported:      rd.GetDeleteSupported(),
+		ListSupported:        rd.GetListSupported(),
+		HealthCheckSupported: rd.GetHealthcheckSupported(),
+	}
+
+	for _, l := range rd.GetLinks() {
+		d.Links = append(d.Links, *linkFromProto(l))
+	}
+
+	var err error
+	if d.PropertiesSchema, err = schemaFromStruct(rd.GetPropertiesSchema(), opts); err != nil {
+		return nil, fmt.Errorf("properties schema: %w", err)
+	}
+	if d.CreateInputSchema, err = schemaFromStruct(rd.GetCreateInputSchema(), opts); err != nil {
+		return nil, fmt.Errorf("create input schema: %w", err)
+	}
+	if d.UpdateInputSchema, err = schemaFromStruct(rd.GetUpdateInputSchema(), opts); err != nil {
+		return nil, fmt.Errorf("update input schema: %w", err)
+	}
+
+	for _, a := range rd.GetActions() {
+		ad := ActionDescriptor{
+			Name:        a.GetName(),
+			DisplayName: a.GetDisplayName(),
+			Description: a.GetDescription(),
+		}
+
+		if ad.InputSchema, err = schemaFromStruct(a.GetInputSchema(), opts); err != nil {
+			return nil, fmt.Errorf("action %s input schema: %w", ad.Name, err)
+		}
+		if ad.OutputSchema, err = schemaFromStruct(a.GetOutputSchema(), opts); err != nil {
+			return nil, fmt.Errorf("action %s output schema: %w", ad.Name, err)
+		}
+
+		d.Actions = append(d.Actions, ad)
+	}
+
+	return d, nil
+}
+
+// schemaFromStruct compiles a schema described by an app. It returns nil for schemas that are not described.
+func schemaFromStruct(s *structpb.Struct, opts []SchemaOption) (*JSONSchema, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	raw, err := s.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+
+	return ParseJSONSchema(raw, opts...)
+}
+
+// Create creates a resource of the type of req.Resource with req.Input.
+func (c *Client) Create(ctx context.Context, req *OperationRequest) (*Resource, error) {
+	return c.operation(ctx, appv1.ResourceOperation_RESOURCE_OPERATION_CREATE, req)
+}
+
+// Read reads the current state of req.Resource.
+func (c *Client) Read(ctx context.Context, req *OperationRequest) (*Resource, error) {
+	return c.operation(ctx, appv1.ResourceOperation_RESOURCE_OPERATION_READ, req)
+}
+
+// Update updates req.Resource with req.Input.
+func (c *Client) Update(ctx context.Context, req *OperationRequest) (*Resource, error) {
+	return c.operation(ctx, appv1.ResourceOperation_RESOURCE_OPERATION_UPDATE, req)
+}
+
+// Delete deletes req.Resource.
+func (c *Client) Delete(ctx context.Context, req *OperationRequest) (*Resource, error) {
+	return c.operation(ctx, appv1.ResourceOperation_RESOURCE_OPERATION_DELETE, req)
+}
+
+func (c *Client) operation(ctx context.Context, op appv1.ResourceOperation, req *OperationRequest) (*Resource, error) {
+	if req == nil || req.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+
+	resource, err := req.Resource.toProto()
+	if err != nil {
+		return nil, fmt.Errorf("convert resource to proto: %w", err)
+	}
+
+	input, err := structpb.NewStruct(req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("convert input to struct: %w", err)
+	}
+
+	res, err := c.service.ExecuteResourceOperation(ctx, connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Metadata:             metadataToProto(req.Metadata),
+		Resource:             resource,
+		Operation:            op,
+		Input:                input,
+		EnvironmentVariables: environmentToProto(req.Environment),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return withType(resourceFromProto(res.Msg.Resource), req.Resource.Type), nil
+}
+
+// ListPage lists a single page of resources of the type of req.Resource, starting at req.Next.
+func (c *Client) ListPage(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	if req == nil || req.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+
+	res, err := c.service.ListResources(ctx, connect.NewRequest(&appv1.ListResourcesRequest{
+		Metadata: metadataToProto(req.Metadata),
+		Resource: &appv1.Resource{Type: req.Resource.Type},
+		Next:     req.Next,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*Resource, 0, len(res.Msg.Resources))
+	for _, r := range res.Msg.Resources {
+		resources = append(resources, withType(resourceFromProto(r), req.Resource.Type))
+	}
+
+	return &ListResponse{
+		Resources: resources,
+		Next:      res.Msg.Next,
+	}, nil
+}
+
+// List lists every resource of the type of req.Resource, following the pages from req.Next until the last one.
+func (c *Client) List(ctx context.Context, req *ListRequest) ([]*Resource, error) {
+	if req == nil || req.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+
+	page := *req
+	seen := make(map[string]bool)
+
+	var resources []*Resource
+	for {
+		res, err := c.ListPage(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, res.Resources...)
+
+		if res.Next == "" {
+			return resources, nil
+		}
+
+		// An app that returns a token it has already returned would never reach the last page.
+		if seen[res.Next] {
+			return nil, fmt.Errorf("list returned the next token %q more than once", res.Next)
+		}
+		seen[res.Next] = true
+		page.Next = res.Next
+	}
+}
+
+// ExecuteAction executes the action req.Action on req.Resource with req.Input.
+func (c *Client) ExecuteAction(ctx context.Context, req *ActionRequest) (*ActionResponse, error) {
+	if req == nil || req.Resource == nil {
+		return nil, errors.New("resource is required")
+	}
+
+	resource, err := req.Resource.toProto()
+	if err != nil {
+		return nil, fmt.Errorf("convert resource to proto: %w", err)
+	}
+
+	input, err := structpb.NewStruct(req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("convert input to struct: %w", err)
+	}
+
+	res, err := c.service.ExecuteResourceAction(ctx, connect.NewRequest(&appv1.ExecuteResourceActionRequest{
+		Metadata:             metadataToProto(req.Metadata),
+		Resource:             resource,
+		Action:               req.Action,
+		Input:                input,
+		EnvironmentVariables: environmentToProto(req.Environment),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ActionResponse{
+		Output: res.Msg.GetOutput().AsMap(),
+	}, nil
+}
+
+// HealthCheck returns the health of the resource type.
+func (c *Client) HealthCheck(ctx context.Context, resourceType string) (*HealthCheckResponse, error) {
+	res, err := c.service.HealthCheck(ctx, connect.NewRequest(&appv1.HealthCheckRequest{
+		Type: resourceType,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	var status HealthCheckStatus
+	switch res.Msg.Status {
+	case appv1.HealthCheckStatus_HEALTH_CHECK_STATUS_HEALTHY:
+		status = HealthCheckStatusHealthy
+	case appv1.HealthCheckStatus_HEALTH_CHECK_STATUS_DEGRADED:
+		status = HealthCheckStatusDegraded
+	case appv1.HealthCheckStatus_HEALTH_CHECK_STATUS_DISRUPTED:
+		status = HealthCheckStatusDisrupted
+	}
+
+	return &HealthCheckResponse{
+		Status:  status,
+		Message: res.Msg.Message,
+	}, nil
+}
+
+// withType sets the Type of a resource returned by a handler that left it empty,
+// as Tempest does with the type of the request.
+func withType(r *Resource, resourceType string) *Resource {
+	if r != nil && r.Type == "" {
+		r.Type = resourceType
+	}
+
+	return r
+}
+
+func environmentToProto(environment map[string]EnvironmentVariable) []*appv1.EnvironmentVariable {
+	vars := make([]*appv1.EnvironmentVariable, 0, len(environment))
+	for _, k := range slices.Sorted(maps.Keys(environment)) {
+		v := environment[k]
+
+		var t appv1.EnvironmentVariableType
+		switch v.Type {
+		case ENVIRONMENT_VARIABLE_TYPE_VAR:
+			t = appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_VAR
+		case ENVIRONMENT_VARIABLE_TYPE_SECRET:
+			t = appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_SECRET
+		case ENVIRONMENT_VARIABLE_TYPE_CERTIFICATE:
+			t = appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_CERTIFICATE
+		case ENVIRONMENT_VARIABLE_TYPE_PRIVATE_KEY:
+			t = appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_PRIVATE_KEY
+		case ENVIRONMENT_VARIABLE_TYPE_PUBLIC_KEY:
+			t = appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_PUBLIC_KEY
+		}
+
+		vars = append(vars, &appv1.EnvironmentVariable{
+			Key:   v.Key,
+			Value: v.Value,
+			Type:  t,
+		})
+	}
+
+	return vars
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+)
+
+const clientSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"project": {"type": "string"},
+		"token": {"type": "string"}
+	}
+}`
+
+// clientRD returns a definition whose handlers echo the Metadata and Environment of the requests
+// in the properties of the resources, and whose list returns one resource per page.
+func clientRD(pages int, next func(page int) string) ResourceDefinition {
+	schema := MustParseJSONSchema([]byte(clientSchema))
+
+	echo := func(_ context.Context, req *OperationRequest) (*OperationResponse, error) {
+		if req.Resource.ExternalID == "missing" {
+			return nil, ErrNotFound
+		}
+
+		id := req.Resource.ExternalID
+		if id == "" {
+			id = "created"
+		}
+
+		return &OperationResponse{
+			Resource: &Resource{
+				ExternalID: id,
+				Properties: map[string]any{
+					"name":    req.Input["name"],
+					"project": req.Metadata.ProjectName,
+					"token":   req.Environment["TOKEN"].Value,
+				},
+			},
+		}, nil
+	}
+
+	rd := ResourceDefinition{
+		Type:             "example",
+		DisplayName:      "Example",
+		Description:      "An example resource",
+		PropertiesSchema: schema,
+		LifecycleStage:   LifecycleStageOperate,
+		Links: []Link{
+			{
+				URL:   "http://example.com",
+				Title: "Example",
+				Type:  LinkTypeDocumentation,
+			},
+		},
+		InstructionsMarkdown: "This is an example resource",
+	}
+
+	rd.CreateFn(echo, schema)
+	rd.ReadFn(echo)
+	rd.UpdateFn(echo, schema)
+	rd.HealthCheckFn(func(_ context.Context) (*HealthCheckResponse, error) {
+		return &HealthCheckResponse{Status: HealthCheckStatusDegraded, Message: "slow"}, nil
+	})
+	rd.ListFn(func(_ context.Context, req *ListRequest) (*ListResponse, error) {
+		page := 0
+		if req.Next != "" {
+			page, _ = strconv.Atoi(req.Next)
+		}
+
+		res := &ListResponse{
+			Resources: []*Resource{{ExternalID: "item-" + strconv.Itoa(page)}},
+		}
+		if page < pages-1 {
+			res.Next = next(page + 1)
+		}
+
+		return res, nil
+	})
+	rd.AddActionDefinition(ActionDefinition{
+		Name:         "rename",
+		DisplayName:  "Rename",
+		Description:  "Rename the resource",
+		InputSchema:  schema,
+		OutputSchema: schema,
+		Handler: func(_ context.Context, req *ActionRequest) (*ActionResponse, error) {
+			return &ActionResponse{
+				Output: map[string]any{
+					"name":  req.Resource.ExternalID + "-" + req.Input["name"].(string),
+					"token": req.Environment["TOKEN"].Value,
+				},
+			}, nil
+		},
+	})
+
+	return rd
+}
+
+func clientRequest(r *Resource, input map[string]any) *OperationRequest {
+	return &OperationRequest{
+		Metadata: &Metadata{ProjectName: "my-project"},
+		Resource: r,
+		Input:    input,
+		Environment: map[string]EnvironmentVariable{
+			"TOKEN": {Key: "TOKEN", Value: "secret", Type: ENVIRONMENT_VARIABLE_TYPE_SECRET},
+		},
+	}
+}
+
+func TestClient(t *testing.T) {
+	a := New(WithResourceDefinition(clientRD(3, strconv.Itoa)))
+
+	mux := http.NewServeMux()
+	mux.Handle(appv1connect.NewAppServiceHandler(a))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	clients := map[string]*Client{
+		"in-process": NewServiceClient(a),
+		"http":       NewClient(srv.Client(), srv.URL),
+	}
+	for name, c := range clients {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			descriptors, err := c.Describe(ctx)
+			require.NoError(t, err)
+			require.Len(t, descriptors, 1)
+
+			d := descriptors[0]
+			assert.Equal(t, "example", d.Type)
+			assert.Equal(t, LifecycleStageOperate, d.LifecycleStage)
+			assert.Equal(t, []Link{{URL: "http://example.com", Title: "Example", Type: LinkTypeDocumentation}}, d.Links)
+			assert.Equal(t, "This is an example resource", d.InstructionsMarkdown)
+			assert.True(t, d.CreateSupported)
+			assert.False(t, d.DeleteSupported)
+			assert.True(t, d.HealthCheckSupported)
+			assert.Equal(t, []string{"name", "project", "token"}, d.PropertiesSchema.PropertyOrder())
+			assert.NotNil(t, d.CreateInputSchema)
+			require.Len(t, d.Actions, 1)
+			assert.Equal(t, "rename", d.Actions[0].Name)
+			assert.NoError(t, d.Actions[0].OutputSchema.Validate(map[string]any{"name": "a"}))
+
+			created, err := c.Create(ctx, clientRequest(&Resource{Type: "example"}, map[string]any{"name": "a"}))
+			require.NoError(t, err)
+			assert.Equal(t, &Resource{
+				ExternalID: "created",
+				Type:       "example",
+				Links:      []*Link{},
+				Properties: map[string]any{"name": "a", "project": "my-project", "token": "secret"},
+			}, created)
+
+			_, err = c.Read(ctx, clientRequest(&Resource{Type: "example", ExternalID: "missing"}, nil))
+			assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+			_, err = c.Delete(ctx, clientRequest(created, nil))
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+			page, err := c.ListPage(ctx, &ListRequest{Resource: &Resource{Type: "example"}, Next: "1"})
+			require.NoError(t, err)
+			assert.Equal(t, "2", page.Next)
+			require.Len(t, page.Resources, 1)
+			assert.Equal(t, "item-1", page.Resources[0].ExternalID)
+			assert.Equal(t, "example", page.Resources[0].Type)
+
+			all, err := c.List(ctx, &ListRequest{Resource: &Resource{Type: "example"}})
+			require.NoError(t, err)
+			var ids []string
+			for _, r := range all {
+				ids = append(ids, r.ExternalID)
+			}
+			assert.Equal(t, []string{"item-0", "item-1", "item-2"}, ids)
+
+			out, err := c.ExecuteAction(ctx, &ActionRequest{
+				Resource:    created,
+				Action:      "rename",
+				Input:       map[string]any{"name": "b"},
+				Environment: clientRequest(nil, nil).Environment,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"name": "created-b", "token": "secret"}, out.Output)
+
+			health, err := c.HealthCheck(ctx, "example")
+			require.NoError(t, err)
+			assert.Equal(t, &HealthCheckResponse{Status: HealthCheckStatusDegraded, Message: "slow"}, health)
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc string
+		call func(c *Client) error
+		err  string
+	}{
+		{
+			desc: "ERR - List Loop",
+			call: func(c *Client) error {
+				_, err := c.List(ctx, &ListRequest{Resource: &Resource{Type: "example"}})
+				return err
+			},
+			err: `list returned the next token "1" more than once`,
+		},
+		{
+			desc: "ERR - Operation Without Resource",
+			call: func(c *Client) error {
+				_, err := c.Create(ctx, &OperationRequest{})
+				return err
+			},
+			err: "resource is required",
+		},
+		{
+			desc: "ERR - List Without Resource",
+			call: func(c *Client) error {
+				_, err := c.ListPage(ctx, &ListRequest{})
+				return err
+			},
+			err: "resource is required",
+		},
+		{
+			desc: "ERR - Invalid Input",
+			call: func(c *Client) error {
+				_, err := c.ExecuteAction(ctx, &ActionRequest{
+					Resource: &Resource{Type: "example"},
+					Action:   "rename",
+					Input:    map[string]any{"name": make(chan int)},
+				})
+				return err
+			},
+			err: "convert input to struct",
+		},
+		{
+			desc: "ERR - Unknown Resource Type",
+			call: func(c *Client) error {
+				_, err := c.HealthCheck(ctx, "unknown")
+				return err
+			},
+			err: "not_found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// The list always returns the token of the second page, so it never ends.
+			c := NewServiceClient(New(WithResourceDefinition(clientRD(3, func(int) string { return "1" }))))
+
+			assert.ErrorContains(t, tc.call(c), tc.err)
+		})
+	}
+}
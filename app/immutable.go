@@ -0,0 +1,105 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// immutableProperties returns the sorted names of the properties marked with x-tempest-immutable
+// in any of the schemas.
+func immutableProperties(schemas ...*JSONSchema) []string {
+	var names []string
+	for _, s := range schemas {
+		for _, name := range s.ImmutableProperties() {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// keepImmutable sets the immutable properties that the update input omits to their value on the
+// existing resource, so that default values injected afterwards cannot change them.
+// Only the properties defined in the update input schema are set.
+func (rd *ResourceDefinition) keepImmutable(inputSchema *JSONSchema, req *OperationRequest) {
+	if req.Resource == nil || inputSchema == nil || req.Input == nil {
+		return
+	}
+
+	for _, name := range immutableProperties(rd.PropertiesSchema, inputSchema) {
+		if _, ok := req.Input[name]; ok {
+			continue
+		}
+		if inputSchema.Properties[name] == nil {
+			continue
+		}
+
+		if current, ok := req.Resource.Properties[name]; ok {
+			req.Input[name] = current
+		}
+	}
+}
+
+// checkImmutable returns an InvalidArgument error if the update input changes a property marked with
+// x-tempest-immutable in the PropertiesSchema or the update input schema.
+//
+// Omitted immutable properties are kept by keepImmutable before default values are injected, so only
+// the properties set in the input are compared. Properties that are not set on the existing resource
+// can still be set.
+func (rd *ResourceDefinition) checkImmutable(inputSchema *JSONSchema, req *OperationRequest) *connect.Error {
+	if req.Resource == nil {
+		return nil
+	}
+
+	var violations []FieldViolation
+	for _, name := range immutableProperties(rd.PropertiesSchema, inputSchema) {
+		value, ok := req.Input[name]
+		if !ok {
+			continue
+		}
+
+		current, ok := req.Resource.Properties[name]
+		if !ok {
+			continue
+		}
+
+		if reflect.DeepEqual(current, value) {
+			continue
+		}
+
+		v := FieldViolation{
+			Field:   jsonPointer([]string{name}),
+			Keyword: AnnotationImmutable,
+			Message: fmt.Sprintf("property %q cannot be changed after the resource is created", name),
+			Value:   value,
+		}
+		if rd.PropertiesSchema.isSecretProperty(name) || inputSchema.isSecretProperty(name) || isSecretValue(value, req.Environment) {
+			v.Value = RedactedValue
+		}
+		violations = append(violations, v)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+	}
+
+	cerr := connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("validate update input: %w", errors.New(strings.Join(messages, "; "))))
+	if detail, err := fieldViolationsDetail(violations); err == nil {
+		cerr.AddDetail(detail)
+	}
+
+	return cerr
+}
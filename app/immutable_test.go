@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+func TestExecuteResourceOperationImmutable(t *testing.T) {
+	properties := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"region": {"type": "string", "x-tempest-immutable": true},
+			"engine": {"type": "string", "default": "postgres"},
+			"size": {"type": "integer"}
+		}
+	}`))
+	updateSchema := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"region": {"type": "string"},
+			"engine": {"type": "string", "default": "postgres", "x-tempest-immutable": true},
+			"size": {"type": "integer"}
+		}
+	}`))
+
+	rd := generateRD(nil)
+	rd.PropertiesSchema = properties
+	rd.UpdateFn(func(_ context.Context, req *OperationRequest) (*OperationResponse, error) {
+		return &OperationResponse{
+			Resource: &Resource{
+				ExternalID: req.Resource.ExternalID,
+				Properties: req.Input,
+			},
+		}, nil
+	}, updateSchema)
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	testCases := []struct {
+		desc       string
+		existing   map[string]any
+		input      map[string]any
+		expected   map[string]any
+		violations []FieldViolation
+	}{
+		{
+			desc:     "OK - Unchanged",
+			existing: map[string]any{"region": "us-east-1", "engine": "mysql", "size": 1},
+			input:    map[string]any{"region": "us-east-1", "engine": "mysql", "size": 2},
+		},
+		{
+			desc:     "OK - Omitted, Current Value Kept Over Default",
+			existing: map[string]any{"region": "us-east-1", "engine": "mysql", "size": 1},
+			input:    map[string]any{"size": 2},
+			expected: map[string]any{"region": "us-east-1", "engine": "mysql", "size": float64(2)},
+		},
+		{
+			desc:     "OK - Omitted, Not Set On Resource",
+			existing: map[string]any{"size": 1},
+			input:    map[string]any{"size": 2},
+			expected: map[string]any{"engine": "postgres", "size": float64(2)},
+		},
+		{
+			desc:     "OK - Not Set On Resource",
+			existing: map[string]any{"size": 1},
+			input:    map[string]any{"region": "us-east-1"},
+		},
+		{
+			desc:     "ERR - Changed",
+			existing: map[string]any{"region": "us-east-1", "engine": "mysql", "size": 1},
+			input:    map[string]any{"region": "eu-west-1", "engine": "postgres"},
+			violations: []FieldViolation{
+				{
+					Field:   "/engine",
+					Keyword: AnnotationImmutable,
+					Message: `property "engine" cannot be changed after the resource is created`,
+					Value:   "postgres",
+				},
+				{
+					Field:   "/region",
+					Keyword: AnnotationImmutable,
+					Message: `property "region" cannot be changed after the resource is created`,
+					Value:   "eu-west-1",
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+				Resource: &appv1.Resource{
+					Type:       "example",
+					ExternalId: "123",
+					Properties: mustNewStruct(tc.existing),
+				},
+				Input:     mustNewStruct(tc.input),
+				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_UPDATE,
+			}))
+			if tc.violations == nil {
+				require.NoError(t, err)
+				if tc.expected != nil {
+					assert.Equal(t, tc.expected, res.Msg.Resource.Properties.AsMap())
+				}
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			assert.Equal(t, tc.violations, FieldViolationsFromError(err))
+		})
+	}
+}
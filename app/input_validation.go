@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// ValidateFunc checks an operation input for rules that JSON schema can't express, such as
+// constraints between fields or lookups in the external system.
+//
+// It is called after the input has been validated against the input schema and default values
+// have been injected, and before the Handler. Return FieldViolations to reject the input with
+// InvalidArgument; any other error is reported as Internal.
+type ValidateFunc func(context.Context, *OperationRequest) error
+
+// FieldViolations is an error that rejects an input because of one or more invalid fields.
+// It can be returned by a ValidateFunc or an ActionDefinition's Validate func.
+//
+// Field must be set to the JSON pointer of the offending input value, for example "/zone".
+// If Value is nil, it is looked up in the input. Values of secret fields are redacted.
+type FieldViolations []FieldViolation
+
+func (v FieldViolations) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, violation := range v {
+		messages = append(messages, fmt.Sprintf("%s: %s", violation.Field, violation.Message))
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// CreateValidateFn adds a ValidateFunc to the Create operation. The Create operation must be set first.
+func (rd *ResourceDefinition) CreateValidateFn(fn ValidateFunc) {
+	if err := rd.setValidate(rd.create, "Create", fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+// UpdateValidateFn adds a ValidateFunc to the Update operation. The Update operation must be set first.
+func (rd *ResourceDefinition) UpdateValidateFn(fn ValidateFunc) {
+	if err := rd.setValidate(rd.update, "Update", fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setValidate(op *operation, name string, fn ValidateFunc) error {
+	if op == nil {
+		return fmt.Errorf("%s operation must be set before adding a ValidateFunc", name)
+	}
+
+	if fn == nil {
+		return fmt.Errorf("ValidateFunc must be set for a %s Operation", name)
+	}
+
+	op.validate = fn
+	return nil
+}
+
+// validationFuncError converts the error returned by a ValidateFunc into a Connect error.
+// FieldViolations are mapped to InvalidArgument and attached as an error detail.
+func validationFuncError(msg string, err error, s *JSONSchema, input map[string]any, env map[string]EnvironmentVariable) *connect.Error {
+	var violations FieldViolations
+	if !errors.As(err, &violations) {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("%s: %w", msg, err))
+	}
+
+	details := make([]FieldViolation, 0, len(violations))
+	for _, v := range violations {
+		location := parseJSONPointer(v.Field)
+
+		if v.Value == nil {
+			v.Value, _ = valueAt(input, location)
+		}
+
+		if v.Value != nil && isRedacted(location, v.Value, s, env) {
+			if value, ok := v.Value.(string); ok && value != "" && strings.Contains(v.Message, value) {
+				v.Message = "value is invalid"
+			}
+			v.Value = RedactedValue
+		}
+		v.Message = redactSecrets(v.Message, env)
+		details = append(details, v)
+	}
+
+	cerr := connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %w", msg, FieldViolations(details)))
+	if detail, err := fieldViolationsDetail(details); err == nil {
+		cerr.AddDetail(detail)
+	}
+
+	return cerr
+}
+
+// redactSecrets replaces the values of the secret environment variables in the message.
+func redactSecrets(message string, env map[string]EnvironmentVariable) string {
+	for _, e := range env {
+		if isSecretVariable(e) {
+			message = strings.ReplaceAll(message, e.Value, RedactedValue)
+		}
+	}
+
+	return message
+}
+
+// parseJSONPointer decodes a JSON pointer, as defined in RFC 6901, into its tokens.
+func parseJSONPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+var crossFieldSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"replicas": {"type": "integer", "default": 1},
+		"zone": {"type": "string"},
+		"name": {"type": "string"},
+		"password": {"type": "string", "writeOnly": true}
+	}
+}`)
+
+func validateCrossField(_ context.Context, req *OperationRequest) error {
+	if req.Input["name"] == "error" {
+		return errors.New("lookup failed")
+	}
+
+	var violations FieldViolations
+	if req.Input["replicas"].(float64) > 1 && req.Input["zone"] != "multi" {
+		violations = append(violations, FieldViolation{
+			Field:   "/zone",
+			Keyword: "multi-az",
+			Message: "zone must be multi-AZ when replicas is greater than 1",
+		})
+	}
+	if req.Input["name"] == "taken" {
+		violations = append(violations, FieldViolation{
+			Field:   "/name",
+			Message: "bucket name already exists",
+		})
+	}
+	if req.Input["password"] == "password" {
+		violations = append(violations, FieldViolation{
+			Field:   "/password",
+			Message: "password is too common",
+		})
+	}
+
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+func TestCreateValidateFn(t *testing.T) {
+	rd := generateRD(nil)
+
+	assert.PanicsWithValue(t, "Create operation must be set before adding a ValidateFunc", func() {
+		rd.CreateValidateFn(validateCrossField)
+	})
+
+	rd.CreateFn(func(context.Context, *OperationRequest) (*OperationResponse, error) {
+		return &OperationResponse{Resource: &Resource{ExternalID: "123"}}, nil
+	}, MustParseJSONSchema(crossFieldSchema))
+	assert.PanicsWithValue(t, "ValidateFunc must be set for a Create Operation", func() {
+		rd.CreateValidateFn(nil)
+	})
+	rd.CreateValidateFn(validateCrossField)
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	testCases := []struct {
+		desc       string
+		input      map[string]any
+		code       connect.Code
+		violations []FieldViolation
+	}{
+		{
+			desc:  "OK",
+			input: map[string]any{"replicas": 3, "zone": "multi"},
+		},
+		{
+			desc:  "OK - Default Injected Before Validation",
+			input: map[string]any{"zone": "a"},
+		},
+		{
+			desc:  "ERR - Violations",
+			input: map[string]any{"replicas": 3, "zone": "a", "name": "taken", "password": "password"},
+			code:  connect.CodeInvalidArgument,
+			violations: []FieldViolation{
+				{Field: "/zone", Keyword: "multi-az", Message: "zone must be multi-AZ when replicas is greater than 1", Value: "a"},
+				{Field: "/name", Message: "bucket name already exists", Value: "taken"},
+				{Field: "/password", Message: "value is invalid", Value: RedactedValue},
+			},
+		},
+		{
+			desc:  "ERR - Internal",
+			input: map[string]any{"name": "error"},
+			code:  connect.CodeInternal,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+				Resource:  &appv1.Resource{Type: "example"},
+				Input:     mustNewStruct(tc.input),
+				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
+			}))
+			if tc.code == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, tc.code, connect.CodeOf(err))
+			assert.Equal(t, tc.violations, FieldViolationsFromError(err))
+		})
+	}
+}
+
+func TestActionValidate(t *testing.T) {
+	rd := generateRD(nil)
+	rd.actions = nil
+	rd.AddActionDefinition(ActionDefinition{
+		Name:        "restart",
+		InputSchema: MustParseJSONSchema(crossFieldSchema),
+		Handler: func(context.Context, *ActionRequest) (*ActionResponse, error) {
+			return &ActionResponse{}, nil
+		},
+		Validate: func(_ context.Context, req *ActionRequest) error {
+			if req.Input["zone"] == "" {
+				return FieldViolations{{Field: "/zone", Message: "zone must not be empty"}}
+			}
+			return nil
+		},
+	})
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	_, err := app.ExecuteResourceAction(context.Background(), connect.NewRequest(&appv1.ExecuteResourceActionRequest{
+		Resource: &appv1.Resource{Type: "example", ExternalId: "123"},
+		Action:   "restart",
+		Input:    mustNewStruct(map[string]any{"zone": ""}),
+	}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.Equal(t, []FieldViolation{{Field: "/zone", Message: "zone must not be empty", Value: ""}}, FieldViolationsFromError(err))
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	assert.Nil(t, parseJSONPointer(""))
+	assert.Equal(t, []string{"a/b", "c~d", "0"}, parseJSONPointer("/a~1b/c~0d/0"))
+}
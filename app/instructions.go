@@ -0,0 +1,287 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// UnknownVariablesError is returned when instruction variables could not be resolved
+// from the properties of a Resource.
+type UnknownVariablesError struct {
+	// Variables are the unresolved variables, in the format resource.<property name>.
+	Variables []string
+}
+
+func (e *UnknownVariablesError) Error() string {
+	return fmt.Sprintf("unknown instruction variables: %s", strings.Join(e.Variables, ", "))
+}
+
+// RenderInstructions substitutes the {{ resource.<property name> }} variables in the
+// Markdown formatted instructions with the properties of the Resource.
+//
+// A variable may list fallbacks separated by "|", which are used in order when a property
+// is missing, null or an empty string. The last fallback may be a double-quoted string literal:
+//
+//	{{ resource.endpoint | resource.hostname | "localhost" }}
+//
+// A variable can be escaped with a backslash, in which case it is rendered verbatim without the backslash:
+//
+//	\{{ resource.name }}
+//
+// Variables that cannot be resolved are rendered as an empty string, and reported
+// in the returned *UnknownVariablesError alongside the rendered instructions.
+func RenderInstructions(markdown string, r *Resource) (string, error) {
+	var properties map[string]any
+	if r != nil {
+		properties = r.Properties
+	}
+
+	out, unknown := renderTemplate(markdown, properties, nil)
+	if len(unknown) > 0 {
+		return out, &UnknownVariablesError{Variables: unknown}
+	}
+
+	return out, nil
+}
+
+// RenderInstructions renders the InstructionsMarkdown of the Resource's ResourceDefinition
+// with the properties of the Resource. Properties missing from the Resource fall back to the
+// defaults in the PropertiesSchema. See the RenderInstructions function for the supported syntax.
+func (a *App) RenderInstructions(r *Resource) (string, error) {
+	if r == nil {
+		return "", errors.New("resource is required")
+	}
+
+	rd, ok := a.getResourceDefinition(r.Type)
+	if !ok {
+		return "", fmt.Errorf("resource type %s not found", r.Type)
+	}
+
+	resource := *r
+	resource.Properties = maps.Clone(r.Properties)
+	if resource.Properties == nil {
+		resource.Properties = map[string]any{}
+	}
+	if rd.PropertiesSchema != nil {
+		rd.PropertiesSchema.injectDefaults(resource.Properties)
+	}
+
+	return RenderInstructions(rd.InstructionsMarkdown, &resource)
+}
+
+// renderTemplate substitutes the variables in the template with the properties.
+// If escape is set, it is applied to every substituted value, along with the output rendered before it
+// and the property the value comes from, which is empty for a literal.
+// It returns the rendered template and the variables that could not be resolved.
+func renderTemplate(tmpl string, properties map[string]any, escape func(prefix, property, v string) string) (string, []string) {
+	var (
+		out     strings.Builder
+		unknown []string
+	)
+	for _, seg := range parseInstructions(tmpl) {
+		if seg.terms == nil {
+			out.WriteString(seg.text)
+			continue
+		}
+
+		v, property, ok := seg.resolve(properties)
+		if !ok {
+			// A variable only fails to resolve when every term is a property, which are all reported.
+			for _, t := range seg.terms {
+				name := "resource." + t.property
+				if !slices.Contains(unknown, name) {
+					unknown = append(unknown, name)
+				}
+			}
+			continue
+		}
+
+		if escape != nil {
+			v = escape(out.String(), property, v)
+		}
+		out.WriteString(v)
+	}
+
+	return out.String(), unknown
+}
+
+// instructionSegment is either literal text, or a variable with its fallbacks.
+type instructionSegment struct {
+	text  string
+	terms []instructionTerm
+}
+
+// instructionTerm is a single resource property reference or string literal within a variable.
+type instructionTerm struct {
+	property  string
+	literal   string
+	isLiteral bool
+}
+
+// resolve returns the value of the first term that resolves against the properties, and the property
+// it comes from, which is empty for a literal.
+func (s instructionSegment) resolve(properties map[string]any) (string, string, bool) {
+	for _, t := range s.terms {
+		if t.isLiteral {
+			return t.literal, "", true
+		}
+
+		v, ok := properties[t.property]
+		if !ok || v == nil || v == "" {
+			continue
+		}
+
+		return formatInstructionValue(v), t.property, true
+	}
+
+	return "", "", false
+}
+
+// formatInstructionValue formats a property value for display in the instructions.
+func formatInstructionValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool, float64, float32, int, int32, int64:
+		return fmt.Sprint(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// instructionVariables returns the property names referenced by {{ resource.<property name> }} variables,
+// including their fallbacks.
+func instructionVariables(markdown string) []string {
+	var vars []string
+	for _, seg := range parseInstructions(markdown) {
+		for _, t := range seg.terms {
+			if !t.isLiteral && !slices.Contains(vars, t.property) {
+				vars = append(vars, t.property)
+			}
+		}
+	}
+
+	return vars
+}
+
+// parseInstructions splits the instructions into literal text and variables.
+// Anything between braces that is not a valid variable expression is kept as literal text.
+func parseInstructions(markdown string) []instructionSegment {
+	var (
+		segs []instructionSegment
+		text strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			segs = append(segs, instructionSegment{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(markdown); {
+		if strings.HasPrefix(markdown[i:], `\{{`) {
+			text.WriteString("{{")
+			i += 3
+			continue
+		}
+
+		if strings.HasPrefix(markdown[i:], "{{") {
+			if terms, n, ok := parseInstructionVariable(markdown[i+2:]); ok {
+				flush()
+				segs = append(segs, instructionSegment{terms: terms})
+				i += n + 2
+				continue
+			}
+		}
+
+		text.WriteByte(markdown[i])
+		i++
+	}
+	flush()
+
+	return segs
+}
+
+// parseInstructionVariable parses a variable expression following the opening braces, for example
+// `resource.name | "default" }}`. The first term must be a resource property reference.
+// It returns the terms and the number of bytes consumed, including the closing braces.
+func parseInstructionVariable(s string) ([]instructionTerm, int, bool) {
+	var terms []instructionTerm
+
+	i := skipSpaces(s, 0)
+	for {
+		var t instructionTerm
+		switch {
+		case strings.HasPrefix(s[i:], `"`):
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, 0, false
+			}
+
+			literal, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, 0, false
+			}
+			t = instructionTerm{literal: literal, isLiteral: true}
+			i = end + 1
+		case strings.HasPrefix(s[i:], "resource."):
+			start := i + len("resource.")
+			end := start
+			for end < len(s) && isPropertyNameChar(s[end]) {
+				end++
+			}
+			if end == start {
+				return nil, 0, false
+			}
+
+			t = instructionTerm{property: s[start:end]}
+			i = end
+		default:
+			return nil, 0, false
+		}
+
+		if len(terms) == 0 && t.isLiteral {
+			return nil, 0, false
+		}
+		terms = append(terms, t)
+
+		i = skipSpaces(s, i)
+		switch {
+		case strings.HasPrefix(s[i:], "}}"):
+			return terms, i + 2, true
+		case strings.HasPrefix(s[i:], "|"):
+			i = skipSpaces(s, i+1)
+		default:
+			return nil, 0, false
+		}
+	}
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isPropertyNameChar(c byte) bool {
+	return c == '_' || c == '-' ||
+		('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderInstructions(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		markdown   string
+		properties map[string]any
+		want       string
+		unknown    []string
+	}{
+		{
+			desc:     "OK - No variables",
+			markdown: "# Setup\nNothing to see here.",
+			want:     "# Setup\nNothing to see here.",
+		},
+		{
+			desc:     "OK - Variables",
+			markdown: "Connect to {{ resource.host }}:{{resource.port}} (tls: {{ resource.tls }}, tags: {{ resource.tags }})",
+			properties: map[string]any{
+				"host": "db.example.com",
+				"port": float64(5432),
+				"tls":  true,
+				"tags": []any{"a", "b"},
+			},
+			want: `Connect to db.example.com:5432 (tls: true, tags: ["a","b"])`,
+		},
+		{
+			desc:     "OK - Fallbacks",
+			markdown: `{{ resource.endpoint | resource.host | "localhost" }} / {{ resource.region | "us-east-1" }} / {{ resource.empty | resource.host }}`,
+			properties: map[string]any{
+				"host":  "db.example.com",
+				"empty": "",
+			},
+			want: "db.example.com / us-east-1 / db.example.com",
+		},
+		{
+			desc:     "OK - Escaped",
+			markdown: `Use \{{ resource.host }} to reference the host, which is {{ resource.host }}.`,
+			properties: map[string]any{
+				"host": "db.example.com",
+			},
+			want: "Use {{ resource.host }} to reference the host, which is db.example.com.",
+		},
+		{
+			desc:     "OK - Not a variable",
+			markdown: `{{ project.name }} {{ "literal" }} {{ resource.name | }}`,
+			want:     `{{ project.name }} {{ "literal" }} {{ resource.name | }}`,
+		},
+		{
+			desc:     "OK - Literal with special characters",
+			markdown: `{{ resource.name | "a \"quoted\" | }}" }}`,
+			want:     `a "quoted" | }}`,
+		},
+		{
+			desc:     "ERR - Unknown variables",
+			markdown: "{{ resource.host }} {{ resource.missing | resource.other }} {{ resource.missing }}",
+			properties: map[string]any{
+				"host": "db.example.com",
+			},
+			want:    "db.example.com  ",
+			unknown: []string{"resource.missing", "resource.other"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			out, err := RenderInstructions(tc.markdown, &Resource{Properties: tc.properties})
+			assert.Equal(t, tc.want, out)
+
+			if tc.unknown != nil {
+				var unknownErr *UnknownVariablesError
+				require.ErrorAs(t, err, &unknownErr)
+				assert.Equal(t, tc.unknown, unknownErr.Variables)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAppRenderInstructions(t *testing.T) {
+	rd := generateRD(nil)
+	rd.PropertiesSchema = MustParseJSONSchema(schemaWithDefaults)
+	rd.InstructionsMarkdown = "{{ resource.property1 }} and {{ resource.property2 }}"
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	out, err := app.RenderInstructions(&Resource{
+		Type: "example",
+		Properties: map[string]any{
+			"property2": "value2",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "default1 and value2", out)
+
+	_, err = app.RenderInstructions(&Resource{Type: "not_found"})
+	assert.EqualError(t, err, "resource type not_found not found")
+
+	_, err = app.RenderInstructions(nil)
+	assert.EqualError(t, err, "resource is required")
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/tidwall/gjson"
@@ -26,6 +27,8 @@ type JSONSchema struct {
 	*jsonschema.Schema
 	// Raw holds the unparsed JSON schema.
 	raw json.RawMessage
+	// opts holds the options the schema was compiled with.
+	opts []SchemaOption
 }
 
 func (j *JSONSchema) toStruct() (*structpb.Struct, error) {
@@ -42,27 +45,35 @@ func (j *JSONSchema) toStruct() (*structpb.Struct, error) {
 	return structpb.NewStruct(m)
 }
 
-// injectDefaults takes the input map and injects default values from the schema.
-func (j *JSONSchema) injectDefaults(input map[string]any) {
-	for property, s := range j.Properties {
-		// No default value, skip
-		if s.Default == nil {
-			continue
-		}
+// MarshalJSON returns the unparsed JSON schema, as it was passed to ParseJSONSchema.
+func (j *JSONSchema) MarshalJSON() ([]byte, error) {
+	if len(j.raw) == 0 {
+		return []byte("{}"), nil
+	}
 
-		// Property already set, skip
-		if _, ok := input[property]; ok {
-			continue
-		}
+	return j.raw, nil
+}
 
-		// Set default value
-		input[property] = *s.Default
+// rawProperty returns the unparsed JSON schema of the named property.
+func (j *JSONSchema) rawProperty(name string) gjson.Result {
+	if j == nil {
+		return gjson.Result{}
 	}
+
+	return gjson.GetBytes(j.raw, "properties."+gjson.Escape(name))
+}
+
+// isSecretProperty reports whether the named property holds sensitive data that must never be echoed back.
+// Properties marked as writeOnly or x-tempest-secret, or with the "password" format, are secret.
+func (j *JSONSchema) isSecretProperty(name string) bool {
+	p := j.rawProperty(name)
+	return p.Get("writeOnly").Bool() || p.Get("format").String() == "password" || j.Annotations(name).Secret
 }
 
 // ParseJSONSchema parses a JSON schema and returns a JSONSchema object.
-// The schema is compiled with annotations extraction enabled.
-func ParseJSONSchema(schema []byte) (*JSONSchema, error) {
+// The schema is compiled with annotations extraction enabled, the BuiltinFormats, and
+// the formats and keywords registered with the options.
+func ParseJSONSchema(schema []byte, opts ...SchemaOption) (*JSONSchema, error) {
 	if len(schema) == 0 {
 		return nil, errors.New("schema is empty")
 	}
@@ -72,6 +83,13 @@ func ParseJSONSchema(schema []byte) (*JSONSchema, error) {
 	}
 	compiler := jsonschema.NewCompiler()
 	compiler.UseLoader(loader)
+	// Enable the Tempest annotations for every draft, including 2019-09 and later
+	// where vocabularies are otherwise only enabled by the metaschema.
+	compiler.RegisterVocabulary(annotationsVocabulary())
+	compiler.AssertVocabs()
+	if err := applySchemaOptions(compiler, opts); err != nil {
+		return nil, fmt.Errorf("schema options: %w", err)
+	}
 
 	unMarshalledSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
 	if err != nil {
@@ -96,13 +114,14 @@ func ParseJSONSchema(schema []byte) (*JSONSchema, error) {
 	return &JSONSchema{
 		Schema: s,
 		raw:    schema,
+		opts:   opts,
 	}, nil
 }
 
 // MustParseJSONSchema parses a JSON schema and returns a JSONSchema object.
 // It will panic if the schema cannot be parsed.
-func MustParseJSONSchema(schema []byte) *JSONSchema {
-	s, err := ParseJSONSchema(schema)
+func MustParseJSONSchema(schema []byte, opts ...SchemaOption) *JSONSchema {
+	s, err := ParseJSONSchema(schema, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -110,6 +129,15 @@ func MustParseJSONSchema(schema []byte) *JSONSchema {
 	return s
 }
 
+// recompile parses the raw schema again, with the options added to the ones it was compiled with.
+func (j *JSONSchema) recompile(opts []SchemaOption) (*JSONSchema, error) {
+	if j == nil || len(j.raw) == 0 {
+		return j, nil
+	}
+
+	return ParseJSONSchema(j.raw, append(slices.Clone(j.opts), opts...)...)
+}
+
 // validateJSONSchema validates the JSON schema against the Tempest product expectations.
 // This is a client side check to assist users with an early feedback loop.
 // The server will reject schemas that do not align with the product expectations.
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Tempest-specific annotation keywords. They describe how a property is presented and edited in Tempest,
+// and are validated when the schema is parsed.
+const (
+	// AnnotationSecret marks a property as sensitive. Its value is masked in Tempest and never echoed back in errors.
+	AnnotationSecret = "x-tempest-secret"
+	// AnnotationMultiline renders a string property as a multi-line text area.
+	AnnotationMultiline = "x-tempest-multiline"
+	// AnnotationOrder sets the position of a property in forms. Properties without an order are displayed last.
+	AnnotationOrder = "x-tempest-order"
+	// AnnotationImmutable marks a property that can be set on create, but not changed afterwards.
+	AnnotationImmutable = "x-tempest-immutable"
+	// AnnotationOptionsSource names the source of the options that Tempest offers for a property,
+	// for values that are only known at runtime.
+	AnnotationOptionsSource = "x-tempest-options-source"
+)
+
+// annotationsVocabularyURL identifies the vocabulary of Tempest annotations.
+const annotationsVocabularyURL = "https://schema.tempestdx.com/vocab/annotations"
+
+// Annotations holds the Tempest annotations of a property.
+type Annotations struct {
+	// Secret is true if the property is marked with x-tempest-secret.
+	Secret bool
+	// Multiline is true if the property is marked with x-tempest-multiline.
+	Multiline bool
+	// Order is the value of x-tempest-order, or nil if it is not set.
+	Order *int
+	// Immutable is true if the property is marked with x-tempest-immutable.
+	Immutable bool
+	// OptionsSource is the value of x-tempest-options-source, or empty if it is not set.
+	OptionsSource string
+}
+
+// Validate implements jsonschema.SchemaExt. Annotations never fail validation.
+func (*Annotations) Validate(*jsonschema.ValidatorContext, any) {}
+
+func annotationsVocabulary() *jsonschema.Vocabulary {
+	return &jsonschema.Vocabulary{
+		URL:     annotationsVocabularyURL,
+		Compile: compileAnnotations,
+	}
+}
+
+// compileAnnotations checks and extracts the Tempest annotations of a schema.
+func compileAnnotations(_ *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+	var a Annotations
+	var found bool
+
+	for _, keyword := range []string{AnnotationSecret, AnnotationMultiline, AnnotationImmutable} {
+		v, ok := obj[keyword]
+		if !ok {
+			continue
+		}
+
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean, got %v", keyword, v)
+		}
+
+		switch keyword {
+		case AnnotationSecret:
+			a.Secret = b
+		case AnnotationMultiline:
+			a.Multiline = b
+		case AnnotationImmutable:
+			a.Immutable = b
+		}
+		found = true
+	}
+
+	if a.Multiline && !allowsString(obj["type"]) {
+		return nil, fmt.Errorf("%s can only be used on properties of type 'string'", AnnotationMultiline)
+	}
+
+	if v, ok := obj[AnnotationOrder]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an integer, got %v", AnnotationOrder, v)
+		}
+
+		order, err := n.Int64()
+		if err != nil || order < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer, got %v", AnnotationOrder, v)
+		}
+
+		o := int(order)
+		a.Order = &o
+		found = true
+	}
+
+	if v, ok := obj[AnnotationOptionsSource]; ok {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s must be a non-empty string, got %v", AnnotationOptionsSource, v)
+		}
+
+		a.OptionsSource = s
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &a, nil
+}
+
+// allowsString reports whether the value of a "type" keyword includes "string".
+func allowsString(t any) bool {
+	switch t := t.(type) {
+	case string:
+		return t == "string"
+	case []any:
+		return slices.Contains(t, any("string"))
+	default:
+		return false
+	}
+}
+
+// Annotations returns the Tempest annotations of the named property.
+// The zero value is returned if the property does not exist or has no annotations.
+func (j *JSONSchema) Annotations(property string) Annotations {
+	if j == nil || j.Schema == nil {
+		return Annotations{}
+	}
+
+	p, ok := j.Properties[property]
+	if !ok {
+		return Annotations{}
+	}
+
+	for _, ext := range p.Extensions {
+		if a, ok := ext.(*Annotations); ok {
+			return *a
+		}
+	}
+
+	return Annotations{}
+}
+
+// SecretProperties returns the sorted names of the properties marked with x-tempest-secret.
+func (j *JSONSchema) SecretProperties() []string {
+	return j.propertiesWhere(func(a Annotations) bool { return a.Secret })
+}
+
+// ImmutableProperties returns the sorted names of the properties marked with x-tempest-immutable.
+func (j *JSONSchema) ImmutableProperties() []string {
+	return j.propertiesWhere(func(a Annotations) bool { return a.Immutable })
+}
+
+// PropertyOrder returns the names of the properties in display order.
+// Properties are sorted by x-tempest-order, then by name. Properties without an order are displayed last.
+func (j *JSONSchema) PropertyOrder() []string {
+	names := j.propertiesWhere(func(Annotations) bool { return true })
+
+	slices.SortStableFunc(names, func(a, b string) int {
+		oa, ob := j.Annotations(a).Order, j.Annotations(b).Order
+		switch {
+		case oa != nil && ob != nil:
+			return *oa - *ob
+		case oa != nil:
+			return -1
+		case ob != nil:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return names
+}
+
+func (j *JSONSchema) propertiesWhere(match func(Annotations) bool) []string {
+	if j == nil || j.Schema == nil {
+		return nil
+	}
+
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(j.Properties)) {
+		if match(j.Annotations(name)) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
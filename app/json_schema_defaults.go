@@ -0,0 +1,243 @@
+package app
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// injectDefaults takes the input map and injects default values from the schema.
+//
+// Defaults are injected for missing properties at every level of the compiled schema, following
+// $ref (including $defs), allOf, if-then-else and array items. The branch of an if-then-else is
+// chosen after the defaults of the enclosing schema have been injected.
+func (j *JSONSchema) injectDefaults(input map[string]any) {
+	if j == nil || j.Schema == nil {
+		return
+	}
+
+	walkSchema(j.Schema, input, nil, injectPropertyDefaults)
+}
+
+// coerceTypes converts scalar input values to the type required by the schema, where it can be done
+// without loss. For example, the string "5" becomes the number 5 when the schema expects an integer.
+func (j *JSONSchema) coerceTypes(input map[string]any) {
+	if j == nil || j.Schema == nil {
+		return
+	}
+
+	walkSchema(j.Schema, input, nil, coerceValue)
+}
+
+// walkSchema calls fn with every schema that applies to the value, and descends into the
+// object properties and array items. The value returned by fn replaces the value.
+// Maps and slices are updated in place.
+func walkSchema(s *jsonschema.Schema, v any, seen map[*jsonschema.Schema]bool, fn func(*jsonschema.Schema, any) any) any {
+	if s == nil || seen[s] {
+		return v
+	}
+
+	// Track the schemas applied to this value to guard against $ref cycles.
+	if seen == nil {
+		seen = make(map[*jsonschema.Schema]bool)
+	}
+	seen[s] = true
+
+	v = fn(s, v)
+
+	switch val := v.(type) {
+	case map[string]any:
+		for name, p := range s.Properties {
+			if pv, ok := val[name]; ok {
+				val[name] = walkSchema(p, pv, nil, fn)
+			}
+		}
+	case []any:
+		switch items := s.Items.(type) {
+		case *jsonschema.Schema:
+			for i := range val {
+				val[i] = walkSchema(items, val[i], nil, fn)
+			}
+		case []*jsonschema.Schema:
+			for i, item := range items {
+				if i < len(val) {
+					val[i] = walkSchema(item, val[i], nil, fn)
+				}
+			}
+		}
+
+		for i, item := range s.PrefixItems {
+			if i < len(val) {
+				val[i] = walkSchema(item, val[i], nil, fn)
+			}
+		}
+
+		if s.Items2020 != nil {
+			for i := len(s.PrefixItems); i < len(val); i++ {
+				val[i] = walkSchema(s.Items2020, val[i], nil, fn)
+			}
+		}
+	}
+
+	v = walkSchema(s.Ref, v, seen, fn)
+	for _, sub := range s.AllOf {
+		v = walkSchema(sub, v, seen, fn)
+	}
+
+	if s.If != nil {
+		if s.If.Validate(v) == nil {
+			v = walkSchema(s.Then, v, seen, fn)
+		} else {
+			v = walkSchema(s.Else, v, seen, fn)
+		}
+	}
+
+	return v
+}
+
+// injectPropertyDefaults sets the default value of every property that is missing from an object.
+func injectPropertyDefaults(s *jsonschema.Schema, v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	for property, p := range s.Properties {
+		// Property already set, skip
+		if _, ok := m[property]; ok {
+			continue
+		}
+
+		// Set default value, if any
+		if d := schemaDefault(p, nil); d != nil {
+			m[property] = copyJSONValue(*d)
+		}
+	}
+
+	return v
+}
+
+// schemaDefault returns the default value of the schema, following $ref and allOf.
+func schemaDefault(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool) *any {
+	if s == nil || seen[s] {
+		return nil
+	}
+
+	if s.Default != nil {
+		return s.Default
+	}
+
+	if seen == nil {
+		seen = make(map[*jsonschema.Schema]bool)
+	}
+	seen[s] = true
+
+	if d := schemaDefault(s.Ref, seen); d != nil {
+		return d
+	}
+
+	for _, sub := range s.AllOf {
+		if d := schemaDefault(sub, seen); d != nil {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// copyJSONValue returns a deep copy of a JSON value, so that defaults are never shared between inputs.
+// Numbers are converted to float64, to match the input values decoded from a structpb.Struct.
+func copyJSONValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return v
+		}
+		return f
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, e := range val {
+			m[k] = copyJSONValue(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(val))
+		for i, e := range val {
+			s[i] = copyJSONValue(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// coerceValue converts a scalar value to one of the types allowed by the schema.
+// Values that already have an allowed type, or that cannot be converted, are returned unchanged.
+func coerceValue(s *jsonschema.Schema, v any) any {
+	if s.Types == nil || s.Types.IsEmpty() {
+		return v
+	}
+
+	allowed := s.Types.ToStrings()
+	if slices.Contains(allowed, jsonTypeOf(v)) || (jsonTypeOf(v) == "integer" && slices.Contains(allowed, "number")) {
+		return v
+	}
+
+	switch val := v.(type) {
+	case string:
+		for _, t := range allowed {
+			switch t {
+			case "integer":
+				if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+					return float64(i)
+				}
+			case "number":
+				if f, err := strconv.ParseFloat(val, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+					return f
+				}
+			case "boolean":
+				if b, err := strconv.ParseBool(val); err == nil {
+					return b
+				}
+			}
+		}
+	case float64:
+		if slices.Contains(allowed, "string") {
+			return strconv.FormatFloat(val, 'f', -1, 64)
+		}
+	case bool:
+		if slices.Contains(allowed, "string") {
+			return strconv.FormatBool(val)
+		}
+	}
+
+	return v
+}
+
+// jsonTypeOf returns the JSON schema type name of a value decoded from JSON or a structpb.Struct.
+// Integral numbers are reported as "integer".
+func jsonTypeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,529 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+	"math/rand/v2"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// exampleMode selects which instance the example generator produces.
+type exampleMode int
+
+const (
+	exampleMinimal exampleMode = iota
+	exampleMaximal
+	exampleRandom
+)
+
+// maxExampleDepth limits the recursion into nested and recursive schemas.
+const maxExampleDepth = 8
+
+// formatExamples are sample values for the formats that are asserted when validating.
+var formatExamples = map[string]string{
+	"date-time":     "2024-01-02T15:04:05Z",
+	"date":          "2024-01-02",
+	"time":          "15:04:05Z",
+	"duration":      "PT1H30M",
+	"go-duration":   "1h30m",
+	"email":         "user@example.com",
+	"hostname":      "example.com",
+	"ipv4":          "192.0.2.1",
+	"ipv6":          "2001:db8::1",
+	"uri":           "https://example.com",
+	"iri":           "https://example.com",
+	"uri-reference": "https://example.com",
+	"iri-reference": "https://example.com",
+	"uri-template":  "https://example.com/{id}",
+	"uuid":          "123e4567-e89b-12d3-a456-426614174000",
+	"json-pointer":  "/example",
+	"regex":         "^example$",
+	"semver":        "1.0.0",
+	"cidr":          "10.0.0.0/16",
+	"aws-arn":       "arn:aws:s3:::example",
+	"k8s-name":      "example",
+	"period":        "2024-01-01T00:00:00Z/P1D",
+}
+
+// MinimalExample returns the smallest valid instance of the schema: only required properties are set,
+// using their default or first example when available, and the lowest values allowed by the constraints.
+// It is useful as a fixture in tests and as an example payload in documentation.
+//
+// An error is returned if the generated instance does not validate against the schema, which can happen
+// for constraints the generator does not understand, such as "not" or conflicting "oneOf" branches.
+func (j *JSONSchema) MinimalExample() (map[string]any, error) {
+	return j.example(&exampleGenerator{mode: exampleMinimal})
+}
+
+// MaximalExample returns a valid instance of the schema with every property set,
+// using defaults and examples when available, and arrays filled up to a few items.
+func (j *JSONSchema) MaximalExample() (map[string]any, error) {
+	return j.example(&exampleGenerator{mode: exampleMaximal})
+}
+
+// RandomExample returns a randomized valid instance of the schema. Optional properties,
+// enum values, numbers, string lengths and array sizes are chosen randomly.
+// The same seed always produces the same instance.
+func (j *JSONSchema) RandomExample(seed uint64) (map[string]any, error) {
+	return j.example(&exampleGenerator{
+		mode: exampleRandom,
+		rand: rand.New(rand.NewPCG(seed, seed)),
+	})
+}
+
+func (j *JSONSchema) example(g *exampleGenerator) (map[string]any, error) {
+	if j == nil || j.Schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+
+	v, ok := g.value(j.Schema, 0, 0).(map[string]any)
+	if !ok {
+		v = map[string]any{}
+	}
+
+	if err := j.Validate(v); err != nil {
+		return nil, fmt.Errorf("generated example does not validate: %w", err)
+	}
+
+	return v, nil
+}
+
+type exampleGenerator struct {
+	mode exampleMode
+	rand *rand.Rand
+}
+
+// value generates a value for the schema. index distinguishes the items of an array with unique items.
+func (g *exampleGenerator) value(s *jsonschema.Schema, index, depth int) any {
+	if s == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	s = resolveExampleSchema(s)
+
+	if s.Const != nil {
+		return copyJSONValue(*s.Const)
+	}
+
+	if s.Enum != nil && len(s.Enum.Values) > 0 {
+		values := s.Enum.Values
+		if g.mode == exampleRandom {
+			return copyJSONValue(values[g.rand.IntN(len(values))])
+		}
+		return copyJSONValue(values[index%len(values)])
+	}
+
+	if index == 0 && g.mode != exampleRandom {
+		if s.Default != nil {
+			return copyJSONValue(*s.Default)
+		}
+		if len(s.Examples) > 0 {
+			return copyJSONValue(s.Examples[0])
+		}
+	}
+
+	switch exampleType(s) {
+	case "null":
+		return nil
+	case "boolean":
+		switch g.mode {
+		case exampleMaximal:
+			return true
+		case exampleRandom:
+			return g.rand.IntN(2) == 1
+		default:
+			return false
+		}
+	case "integer":
+		return g.number(s, index, true)
+	case "number":
+		return g.number(s, index, false)
+	case "string":
+		return g.string(s, index)
+	case "array":
+		return g.array(s, depth)
+	default:
+		return g.object(s, depth)
+	}
+}
+
+// resolveExampleSchema follows references and merges the properties and required of allOf subschemas,
+// so that the generator can work on a single schema.
+func resolveExampleSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	for s.Ref != nil && s.Types == nil && s.Properties == nil {
+		s = s.Ref
+	}
+
+	if len(s.AllOf) == 0 {
+		return s
+	}
+
+	merged := *s
+	merged.AllOf = nil
+	merged.Properties = maps.Clone(s.Properties)
+	merged.Required = slices.Clone(s.Required)
+	for _, sub := range s.AllOf {
+		sub = resolveExampleSchema(sub)
+		if merged.Types == nil {
+			merged.Types = sub.Types
+		}
+		if merged.Enum == nil {
+			merged.Enum = sub.Enum
+		}
+		if merged.Const == nil {
+			merged.Const = sub.Const
+		}
+		if merged.Default == nil {
+			merged.Default = sub.Default
+		}
+		if merged.Items == nil && merged.Items2020 == nil {
+			merged.Items, merged.Items2020 = sub.Items, sub.Items2020
+		}
+		if len(sub.Properties) > 0 && merged.Properties == nil {
+			merged.Properties = map[string]*jsonschema.Schema{}
+		}
+		for name, p := range sub.Properties {
+			if _, ok := merged.Properties[name]; !ok {
+				merged.Properties[name] = p
+			}
+		}
+		for _, name := range sub.Required {
+			if !slices.Contains(merged.Required, name) {
+				merged.Required = append(merged.Required, name)
+			}
+		}
+	}
+
+	return &merged
+}
+
+// exampleType returns the type of value to generate for the schema, preferring non-null types.
+func exampleType(s *jsonschema.Schema) string {
+	if s.Types == nil || s.Types.IsEmpty() {
+		switch {
+		case s.Properties != nil || s.Required != nil:
+			return "object"
+		case s.Items != nil || s.Items2020 != nil:
+			return "array"
+		case s.Pattern != nil || s.MinLength != nil || s.MaxLength != nil || s.Format != nil:
+			return "string"
+		case s.Minimum != nil || s.Maximum != nil || s.MultipleOf != nil:
+			return "number"
+		default:
+			return "object"
+		}
+	}
+
+	types := s.Types.ToStrings()
+	for _, t := range []string{"object", "string", "integer", "number", "boolean", "array"} {
+		if slices.Contains(types, t) {
+			return t
+		}
+	}
+
+	return "null"
+}
+
+func (g *exampleGenerator) object(s *jsonschema.Schema, depth int) map[string]any {
+	o := map[string]any{}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		required := slices.Contains(s.Required, name)
+
+		switch g.mode {
+		case exampleMinimal:
+			if !required {
+				continue
+			}
+		case exampleRandom:
+			if !required && g.rand.IntN(2) == 0 {
+				continue
+			}
+		}
+
+		o[name] = g.value(s.Properties[name], 0, depth+1)
+	}
+
+	// Required properties without a schema accept any value.
+	for _, name := range s.Required {
+		if _, ok := o[name]; !ok {
+			o[name] = "example"
+		}
+	}
+
+	return o
+}
+
+func (g *exampleGenerator) array(s *jsonschema.Schema, depth int) []any {
+	items, _ := s.Items.(*jsonschema.Schema)
+	if s.Items2020 != nil {
+		items = s.Items2020
+	}
+
+	minItems, maxItems := 0, 3
+	if s.MinItems != nil {
+		minItems = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		maxItems = *s.MaxItems
+	}
+	maxItems = max(min(maxItems, minItems+3), minItems)
+
+	n := minItems
+	switch g.mode {
+	case exampleMaximal:
+		n = maxItems
+	case exampleRandom:
+		n = minItems + g.rand.IntN(maxItems-minItems+1)
+	}
+
+	// Arrays with unique items are limited to the number of enum values, if any.
+	if s.UniqueItems && items != nil {
+		if r := resolveExampleSchema(items); r.Enum != nil {
+			n = max(min(n, len(r.Enum.Values)), minItems)
+		}
+	}
+
+	a := make([]any, 0, n)
+	for i := range n {
+		if g.mode == exampleRandom && s.UniqueItems {
+			// Random values may collide, so generate deterministic ones instead.
+			a = append(a, (&exampleGenerator{mode: exampleMaximal}).value(items, i, depth+1))
+			continue
+		}
+
+		index := 0
+		if s.UniqueItems {
+			index = i
+		}
+		a = append(a, g.value(items, index, depth+1))
+	}
+
+	return a
+}
+
+func (g *exampleGenerator) number(s *jsonschema.Schema, index int, integer bool) any {
+	step := big.NewRat(1, 1)
+	if !integer {
+		step = big.NewRat(1, 2)
+	}
+	if s.MultipleOf != nil {
+		step = s.MultipleOf
+	}
+
+	lo, hi := s.Minimum, s.Maximum
+	if s.ExclusiveMinimum != nil {
+		lo = new(big.Rat).Add(s.ExclusiveMinimum, step)
+	}
+	if s.ExclusiveMaximum != nil {
+		hi = new(big.Rat).Sub(s.ExclusiveMaximum, step)
+	}
+
+	switch {
+	case lo == nil && hi == nil:
+		lo, hi = big.NewRat(0, 1), big.NewRat(100, 1)
+	case lo == nil:
+		lo = new(big.Rat).Sub(hi, big.NewRat(100, 1))
+	case hi == nil:
+		hi = new(big.Rat).Add(lo, big.NewRat(100, 1))
+	}
+
+	// Start from the lowest multiple of step within the bounds, unless 0 is allowed.
+	first := ceilMultiple(lo, step)
+	if lo.Sign() <= 0 && hi.Sign() >= 0 && g.mode == exampleMinimal {
+		first = big.NewRat(0, 1)
+	}
+	count := new(big.Rat).Quo(new(big.Rat).Sub(hi, first), step)
+	steps := new(big.Int).Quo(count.Num(), count.Denom()).Int64()
+	if steps < 0 {
+		steps = 0
+	}
+
+	var n int64
+	switch g.mode {
+	case exampleMaximal:
+		n = steps
+	case exampleRandom:
+		n = g.rand.Int64N(steps + 1)
+	}
+	n = min(n+int64(index), steps)
+
+	v := new(big.Rat).Add(first, new(big.Rat).Mul(step, big.NewRat(n, 1)))
+	f, _ := v.Float64()
+	if integer && v.IsInt() {
+		return float64(v.Num().Int64())
+	}
+
+	return f
+}
+
+// ceilMultiple returns the lowest multiple of step that is greater than or equal to v.
+func ceilMultiple(v, step *big.Rat) *big.Rat {
+	q := new(big.Rat).Quo(v, step)
+	n := new(big.Int).Quo(q.Num(), q.Denom())
+	if new(big.Rat).SetInt(n).Cmp(q) < 0 {
+		n.Add(n, big.NewInt(1))
+	}
+
+	return new(big.Rat).Mul(new(big.Rat).SetInt(n), step)
+}
+
+func (g *exampleGenerator) string(s *jsonschema.Schema, index int) string {
+	minLength, maxLength := 0, -1
+	if s.MinLength != nil {
+		minLength = *s.MinLength
+	}
+	if s.MaxLength != nil {
+		maxLength = *s.MaxLength
+	}
+
+	var v string
+	switch {
+	case s.Format != nil && formatExamples[s.Format.Name] != "":
+		v = formatExamples[s.Format.Name]
+		if index > 0 && s.Format.Name == "k8s-name" {
+			v += "-" + strconv.Itoa(index)
+		}
+		return v
+	case s.Pattern != nil:
+		if p, ok := g.pattern(s.Pattern.String(), minLength); ok {
+			return p
+		}
+	}
+
+	v = "example"
+	if g.mode == exampleRandom {
+		v = g.letters(4 + g.rand.IntN(8))
+	}
+	if index > 0 {
+		v += strconv.Itoa(index)
+	}
+
+	if len(v) < minLength {
+		v += strings.Repeat("x", minLength-len(v))
+	}
+	if maxLength >= 0 && len(v) > maxLength {
+		v = v[len(v)-maxLength:]
+	}
+
+	return v
+}
+
+func (g *exampleGenerator) letters(n int) string {
+	var sb strings.Builder
+	for range n {
+		sb.WriteByte(byte('a' + g.rand.IntN(26)))
+	}
+	return sb.String()
+}
+
+// pattern generates a string matching the regular expression, repeating the pattern's
+// repeatable parts until minLength is reached.
+func (g *exampleGenerator) pattern(expr string, minLength int) (string, bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+
+	// The pattern is not anchored by default, so generating a match of the whole expression is enough.
+	for extra := 0; extra <= minLength; extra++ {
+		var sb strings.Builder
+		if !g.regexp(&sb, re, extra) {
+			return "", false
+		}
+		if len(sb.String()) >= minLength {
+			return sb.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// regexp writes a string matching re to sb. extra repetitions are added to unbounded repeats.
+func (g *exampleGenerator) regexp(sb *strings.Builder, re *syntax.Regexp, extra int) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+		return true
+	case syntax.OpCharClass:
+		if len(re.Rune) < 2 {
+			return false
+		}
+		if g.mode == exampleRandom {
+			pair := g.rand.IntN(len(re.Rune)/2) * 2
+			lo, hi := re.Rune[pair], re.Rune[pair+1]
+			sb.WriteRune(lo + rune(g.rand.IntN(int(min(hi-lo, 25))+1)))
+			return true
+		}
+		sb.WriteRune(classRune(re.Rune))
+		return true
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte('a')
+		return true
+	case syntax.OpCapture:
+		return g.regexp(sb, re.Sub[0], extra)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.regexp(sb, sub, extra) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		sub := re.Sub[0]
+		if g.mode == exampleRandom {
+			sub = re.Sub[g.rand.IntN(len(re.Sub))]
+		}
+		return g.regexp(sb, sub, extra)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, -1
+		switch re.Op {
+		case syntax.OpPlus:
+			lo = 1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo, hi = re.Min, re.Max
+		}
+
+		n := lo
+		if g.mode == exampleMaximal || g.mode == exampleRandom {
+			n = max(lo, 1)
+		}
+		n += extra
+		if hi >= 0 {
+			n = min(n, hi)
+		}
+
+		for range n {
+			if !g.regexp(sb, re.Sub[0], extra) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// classRune picks a readable rune from the ranges of a character class, preferring letters and digits.
+func classRune(ranges []rune) rune {
+	for _, want := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= want && want <= ranges[i+1] {
+				return want
+			}
+		}
+	}
+
+	return ranges[0]
+}
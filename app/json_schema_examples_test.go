@@ -0,0 +1,105 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exampleSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"name": {"type": "string", "pattern": "^[a-z][a-z0-9-]{2,}$", "maxLength": 20},
+		"engine": {"type": "string", "enum": ["postgres", "mysql"]},
+		"replicas": {"type": "integer", "minimum": 1, "maximum": 5},
+		"storage": {"type": "number", "exclusiveMinimum": 10, "multipleOf": 5},
+		"tier": {"type": "string", "default": "standard"},
+		"public": {"type": "boolean"},
+		"cidr": {"type": "string", "format": "cidr"},
+		"zones": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "uniqueItems": true, "minItems": 1},
+		"labels": {"type": "array", "items": {"type": "string", "minLength": 3}},
+		"description": {"type": ["string", "null"], "minLength": 10}
+	},
+	"required": ["name", "engine", "replicas", "storage", "zones"]
+}`)
+
+func TestMinimalExample(t *testing.T) {
+	s := MustParseJSONSchema(exampleSchema)
+
+	example, err := s.MinimalExample()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"name":     "aaa",
+		"engine":   "postgres",
+		"replicas": float64(1),
+		"storage":  float64(15),
+		"zones":    []any{"a"},
+	}, example)
+}
+
+func TestMaximalExample(t *testing.T) {
+	s := MustParseJSONSchema(exampleSchema)
+
+	example, err := s.MaximalExample()
+	require.NoError(t, err)
+
+	assert.Len(t, example, 10)
+	assert.Equal(t, float64(5), example["replicas"])
+	assert.Equal(t, float64(115), example["storage"])
+	assert.Equal(t, "standard", example["tier"])
+	assert.Equal(t, true, example["public"])
+	assert.Equal(t, "10.0.0.0/16", example["cidr"])
+	assert.Equal(t, []any{"a", "b"}, example["zones"])
+	assert.Len(t, example["labels"], 3)
+	assert.Equal(t, "examplexxx", example["description"])
+}
+
+func TestRandomExample(t *testing.T) {
+	s := MustParseJSONSchema(exampleSchema)
+
+	for seed := range uint64(50) {
+		example, err := s.RandomExample(seed)
+		require.NoError(t, err, "seed %d", seed)
+
+		again, err := s.RandomExample(seed)
+		require.NoError(t, err)
+		assert.Equal(t, example, again, "seed %d", seed)
+	}
+
+	a, err := s.RandomExample(1)
+	require.NoError(t, err)
+	b, err := s.RandomExample(2)
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func TestExampleNestedDefaults(t *testing.T) {
+	s := MustParseJSONSchema(nestedDefaultsSchema)
+
+	example, err := s.MinimalExample()
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate(example))
+
+	example, err = s.MaximalExample()
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate(example))
+}
+
+func TestExampleInvalid(t *testing.T) {
+	s := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"name": {"type": "string", "not": {"type": "string"}}
+		},
+		"required": ["name"]
+	}`))
+
+	_, err := s.MinimalExample()
+	assert.ErrorContains(t, err, "generated example does not validate")
+
+	var nilSchema *JSONSchema
+	_, err = nilSchema.MinimalExample()
+	assert.EqualError(t, err, "schema is nil")
+}
@@ -0,0 +1,311 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/message"
+)
+
+// Format validates the string values of schemas that use the "format" keyword with its Name.
+//
+// Formats are asserted for draft-07 schemas and earlier. For draft 2019-09 and later, including
+// schemas without a $schema keyword which are draft 2020-12 schemas, "format" is an annotation
+// unless the metaschema requires the format-assertion vocabulary, or WithFormatAssertion is used.
+type Format struct {
+	// Name is the value of the "format" keyword, for example "cidr".
+	Name string
+	// Validate returns an error if the value does not match the format.
+	// Values of other types than the one the format applies to should be accepted.
+	Validate func(v any) error
+}
+
+// KeywordValidator validates values against a custom schema keyword, such as "x-allowed-prefixes".
+type KeywordValidator struct {
+	// Keyword is the name of the keyword.
+	Keyword string
+	// Validate returns an error if the value does not satisfy the keyword.
+	// keywordValue is the value of the keyword in the schema, as decoded from JSON with numbers as json.Number.
+	Validate func(keywordValue, v any) error
+}
+
+// SchemaOption configures how JSON schemas are compiled.
+type SchemaOption func(*schemaOptions)
+
+type schemaOptions struct {
+	formats      []Format
+	keywords     []KeywordValidator
+	assertFormat bool
+}
+
+// WithFormat registers a custom format. It replaces the built-in format with the same name.
+func WithFormat(f Format) SchemaOption {
+	return func(o *schemaOptions) {
+		o.formats = append(o.formats, f)
+	}
+}
+
+// WithFormatAssertion asserts the "format" keyword for every draft, including draft 2019-09 and later
+// and schemas without a $schema keyword, where formats are otherwise only annotations.
+func WithFormatAssertion() SchemaOption {
+	return func(o *schemaOptions) {
+		o.assertFormat = true
+	}
+}
+
+// WithKeywordValidator registers a custom keyword.
+func WithKeywordValidator(k KeywordValidator) SchemaOption {
+	return func(o *schemaOptions) {
+		o.keywords = append(o.keywords, k)
+	}
+}
+
+// BuiltinFormats returns the formats that are registered when compiling every schema,
+// in addition to the formats defined by the JSON schema specification:
+//
+//   - cidr: an IPv4 or IPv6 CIDR block, such as "10.0.0.0/16"
+//   - aws-arn: an Amazon Resource Name, such as "arn:aws:s3:::my-bucket"
+//   - semver: a semantic version, such as "1.2.3-rc.1"
+//   - k8s-name: a Kubernetes object name, as defined by RFC 1123 DNS subdomains
+//   - go-duration: a Go duration, such as "1h30m", or an ISO 8601 duration, such as "PT1H30M"
+//
+// The "duration" format of the specification only accepts ISO 8601 durations.
+func BuiltinFormats() []Format {
+	return []Format{
+		{Name: "cidr", Validate: validateCIDR},
+		{Name: "aws-arn", Validate: validateAWSARN},
+		{Name: "semver", Validate: validateSemver},
+		{Name: "k8s-name", Validate: validateK8sName},
+		{Name: "go-duration", Validate: validateDuration},
+	}
+}
+
+// applySchemaOptions registers the built-in formats and the configured formats and keywords on the compiler.
+func applySchemaOptions(c *jsonschema.Compiler, opts []SchemaOption) error {
+	var o schemaOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.assertFormat {
+		c.AssertFormat()
+	}
+
+	for _, f := range append(BuiltinFormats(), o.formats...) {
+		if f.Name == "" || f.Validate == nil {
+			return errors.New("format name and Validate func must be set")
+		}
+		c.RegisterFormat(&jsonschema.Format{Name: f.Name, Validate: f.Validate})
+	}
+
+	for _, k := range o.keywords {
+		if k.Keyword == "" || k.Validate == nil {
+			return errors.New("keyword name and Validate func must be set")
+		}
+		c.RegisterVocabulary(keywordVocabulary(k))
+	}
+
+	return nil
+}
+
+// keywordVocabulary wraps a KeywordValidator in a jsonschema vocabulary.
+func keywordVocabulary(k KeywordValidator) *jsonschema.Vocabulary {
+	return &jsonschema.Vocabulary{
+		URL: "https://schema.tempestdx.com/vocab/keyword/" + k.Keyword,
+		Compile: func(_ *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			value, ok := obj[k.Keyword]
+			if !ok {
+				return nil, nil
+			}
+
+			return &keywordExt{validator: k, value: value}, nil
+		},
+	}
+}
+
+type keywordExt struct {
+	validator KeywordValidator
+	value     any
+}
+
+func (e *keywordExt) Validate(ctx *jsonschema.ValidatorContext, v any) {
+	if err := e.validator.Validate(e.value, v); err != nil {
+		ctx.AddError(&keywordError{keyword: e.validator.Keyword, err: err})
+	}
+}
+
+// keywordError is the jsonschema.ErrorKind reported by a KeywordValidator.
+type keywordError struct {
+	keyword string
+	err     error
+}
+
+func (k *keywordError) KeywordPath() []string {
+	return []string{k.keyword}
+}
+
+func (k *keywordError) LocalizedString(*message.Printer) string {
+	return k.err.Error()
+}
+
+// specFormat returns the validation function of a format defined by the JSON schema specification.
+func specFormat(name string) func(v any) error {
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource("format.json", map[string]any{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"format":  name,
+	}); err != nil {
+		panic(err)
+	}
+
+	s, err := c.Compile("format.json")
+	if err != nil {
+		panic(err)
+	}
+
+	return s.Format.Validate
+}
+
+var (
+	validateSemver = specFormat("semver")
+	isoDuration    = specFormat("duration")
+)
+
+func validateCIDR(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if _, err := netip.ParsePrefix(s); err != nil {
+		return fmt.Errorf("invalid CIDR block: %w", err)
+	}
+
+	return nil
+}
+
+var awsARNRegex = regexp.MustCompile(`^arn:aws[a-z-]*:[a-z0-9-]+:[a-z0-9-]*:(\d{12})?:.+$`)
+
+func validateAWSARN(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if !awsARNRegex.MatchString(s) {
+		return errors.New("invalid AWS ARN, expected arn:partition:service:region:account-id:resource")
+	}
+
+	return nil
+}
+
+var k8sNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+func validateK8sName(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if len(s) > 253 {
+		return errors.New("must be no more than 253 characters")
+	}
+
+	if !k8sNameRegex.MatchString(s) {
+		return errors.New("must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character")
+	}
+
+	return nil
+}
+
+func validateDuration(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if strings.HasPrefix(s, "P") {
+		return isoDuration(s)
+	}
+
+	if _, err := time.ParseDuration(s); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	return nil
+}
+
+// recompileSchemas returns a copy of the ResourceDefinition with every schema compiled again with the options.
+// Schemas shared between operations, such as the PropertiesSchema, remain shared in the copy.
+func (rd ResourceDefinition) recompileSchemas(opts []SchemaOption) (ResourceDefinition, error) {
+	compiled := make(map[*JSONSchema]*JSONSchema)
+	recompile := func(s *JSONSchema) (*JSONSchema, error) {
+		if c, ok := compiled[s]; ok {
+			return c, nil
+		}
+
+		c, err := s.recompile(opts)
+		if err != nil {
+			return nil, err
+		}
+		compiled[s] = c
+
+		return c, nil
+	}
+
+	var err error
+	if rd.PropertiesSchema, err = recompile(rd.PropertiesSchema); err != nil {
+		return rd, fmt.Errorf("properties schema: %w", err)
+	}
+
+	for _, op := range []struct {
+		name string
+		op   **operation
+	}{
+		{"create", &rd.create},
+		{"update", &rd.update},
+		{"read", &rd.read},
+		{"delete", &rd.delete},
+	} {
+		if *op.op == nil {
+			continue
+		}
+
+		c := **op.op
+		if c.schema.input, err = recompile(c.schema.input); err != nil {
+			return rd, fmt.Errorf("%s input schema: %w", op.name, err)
+		}
+		if c.schema.output, err = recompile(c.schema.output); err != nil {
+			return rd, fmt.Errorf("%s output schema: %w", op.name, err)
+		}
+		*op.op = &c
+	}
+
+	if rd.list != nil {
+		l := *rd.list
+		if l.schema.output, err = recompile(l.schema.output); err != nil {
+			return rd, fmt.Errorf("list output schema: %w", err)
+		}
+		rd.list = &l
+	}
+
+	actions := make([]ActionDefinition, len(rd.actions))
+	for i, ad := range rd.actions {
+		if ad.InputSchema, err = recompile(ad.InputSchema); err != nil {
+			return rd, fmt.Errorf("action %s input schema: %w", ad.Name, err)
+		}
+		if ad.OutputSchema, err = recompile(ad.OutputSchema); err != nil {
+			return rd, fmt.Errorf("action %s output schema: %w", ad.Name, err)
+		}
+		actions[i] = ad
+	}
+	rd.actions = actions
+
+	return rd, nil
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+func formatSchema(format string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"value": {"type": "string", "format": %q}
+		}
+	}`, format))
+}
+
+func TestBuiltinFormats(t *testing.T) {
+	testCases := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			format:  "cidr",
+			valid:   []string{"10.0.0.0/16", "2001:db8::/32"},
+			invalid: []string{"10.0.0.0", "10.0.0.0/33", "example"},
+		},
+		{
+			format:  "aws-arn",
+			valid:   []string{"arn:aws:s3:::my-bucket", "arn:aws:iam::123456789012:role/admin", "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-1234"},
+			invalid: []string{"arn:gcp:s3:::my-bucket", "arn:aws:iam::1234:role/admin", "my-bucket"},
+		},
+		{
+			format:  "semver",
+			valid:   []string{"1.2.3", "1.2.3-rc.1+build.5"},
+			invalid: []string{"1.2", "v1.2.3", "01.2.3"},
+		},
+		{
+			format:  "k8s-name",
+			valid:   []string{"my-app", "my-app.example.com", "a"},
+			invalid: []string{"My-App", "-my-app", "my_app", strings.Repeat("a", 254)},
+		},
+		{
+			format:  "go-duration",
+			valid:   []string{"30s", "1h30m", "PT1H30M", "P1D"},
+			invalid: []string{"30", "1 hour", "P"},
+		},
+		{
+			format:  "duration",
+			valid:   []string{"PT1H30M", "P1D"},
+			invalid: []string{"30s", "1h30m", "P"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			s := MustParseJSONSchema(formatSchema(tc.format))
+
+			for _, v := range tc.valid {
+				assert.NoError(t, s.Validate(map[string]any{"value": v}), v)
+			}
+			for _, v := range tc.invalid {
+				assert.Error(t, s.Validate(map[string]any{"value": v}), v)
+			}
+		})
+	}
+}
+
+func TestFormatAssertion(t *testing.T) {
+	schemas := map[string][]byte{
+		"no $schema": []byte(`{
+			"properties": {
+				"c": {"type": "string", "format": "cidr"},
+				"e": {"type": "string", "format": "email"},
+				"n": {"type": "string", "format": "even-length"}
+			}
+		}`),
+		"draft 2020-12": []byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"properties": {
+				"c": {"type": "string", "format": "cidr"},
+				"e": {"type": "string", "format": "email"},
+				"n": {"type": "string", "format": "even-length"}
+			}
+		}`),
+	}
+	invalid := []map[string]any{
+		{"c": "nope"},
+		{"e": "nope"},
+		{"n": "abc"},
+	}
+
+	for desc, raw := range schemas {
+		t.Run(desc, func(t *testing.T) {
+			// Formats are annotations by default, so the values accepted before are still accepted.
+			s := MustParseJSONSchema(raw, WithFormat(evenFormat))
+			for _, input := range invalid {
+				assert.NoError(t, s.Validate(input), input)
+			}
+
+			s = MustParseJSONSchema(raw, WithFormat(evenFormat), WithFormatAssertion())
+			require.NoError(t, s.Validate(map[string]any{"c": "10.0.0.0/16", "e": "dev@example.com", "n": "ab"}))
+			for _, input := range invalid {
+				assert.Error(t, s.Validate(input), input)
+			}
+		})
+	}
+}
+
+var evenFormat = Format{
+	Name: "even-length",
+	Validate: func(v any) error {
+		if s, ok := v.(string); ok && len(s)%2 != 0 {
+			return errors.New("length must be even")
+		}
+		return nil
+	},
+}
+
+var prefixKeyword = KeywordValidator{
+	Keyword: "x-prefix",
+	Validate: func(keywordValue, v any) error {
+		prefix, _ := keywordValue.(string)
+		if s, ok := v.(string); ok && !strings.HasPrefix(s, prefix) {
+			return fmt.Errorf("must start with %q", prefix)
+		}
+		return nil
+	},
+}
+
+func TestParseJSONSchemaOptions(t *testing.T) {
+	raw := []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"code": {"type": "string", "format": "even-length"},
+			"bucket": {"type": "string", "x-prefix": "tempest-"}
+		}
+	}`)
+
+	s := MustParseJSONSchema(raw)
+	require.NoError(t, s.Validate(map[string]any{"code": "abc", "bucket": "other"}))
+
+	s = MustParseJSONSchema(raw, WithFormat(evenFormat), WithKeywordValidator(prefixKeyword))
+	require.NoError(t, s.Validate(map[string]any{"code": "ab", "bucket": "tempest-logs"}))
+
+	err := s.Validate(map[string]any{"code": "abc", "bucket": "other"})
+	require.Error(t, err)
+
+	violations := fieldViolations(err, s, map[string]any{"code": "abc", "bucket": "other"}, nil)
+	assert.ElementsMatch(t, []FieldViolation{
+		{Field: "/code", Keyword: "format", Message: "'abc' is not valid even-length: length must be even", Value: "abc"},
+		{Field: "/bucket", Keyword: "x-prefix", Message: `must start with "tempest-"`, Value: "other"},
+	}, violations)
+
+	_, err = ParseJSONSchema(raw, WithFormat(Format{Name: "even-length"}))
+	assert.EqualError(t, err, "schema options: format name and Validate func must be set")
+}
+
+func TestWithSchemaOptions(t *testing.T) {
+	rd := generateRD(nil)
+	rd.CreateFn(simpleOpFn, MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"bucket": {"type": "string", "x-prefix": "tempest-"}
+		}
+	}`)))
+
+	app := New(
+		WithResourceDefinition(rd),
+		WithSchemaOptions(WithKeywordValidator(prefixKeyword)),
+	)
+
+	_, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource:  &appv1.Resource{Type: "example"},
+		Input:     mustNewStruct(map[string]any{"bucket": "other"}),
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
+	}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	// The ResourceDefinition passed to New is left unchanged.
+	require.NoError(t, rd.create.schema.input.Validate(map[string]any{"bucket": "other"}))
+	assert.Same(t, app.resourceDefinitions[0].PropertiesSchema, app.resourceDefinitions[0].create.schema.output)
+}
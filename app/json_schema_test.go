@@ -1,7 +1,9 @@
 package app
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -201,6 +203,35 @@ func TestJSONSchemaToStruct(t *testing.T) {
 	}
 }
 
+func TestJSONSchemaMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		schema *JSONSchema
+		want   string
+	}{
+		{
+			desc:   "OK - Raw Schema",
+			schema: MustParseJSONSchema(GenericEmptySchema),
+			want:   string(GenericEmptySchema),
+		},
+		{
+			desc: "OK - Empty Raw",
+			schema: &JSONSchema{
+				Schema: &jsonschema.Schema{},
+			},
+			want: `{}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b, err := json.Marshal(map[string]any{"schema": tc.schema})
+			require.NoError(t, err)
+
+			assert.JSONEq(t, `{"schema": `+tc.want+`}`, string(b))
+		})
+	}
+}
+
 func TestInjectDefaults(t *testing.T) {
 	testCases := []struct {
 		desc   string
@@ -239,6 +270,143 @@ func TestInjectDefaults(t *testing.T) {
 	}
 }
 
+var nestedDefaultsSchema = []byte(`{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"tier": {
+			"type": "string",
+			"default": "standard"
+		},
+		"rule": {
+			"type": "object",
+			"properties": {
+				"action": {"type": "string", "default": "allow"}
+			}
+		}
+	},
+	"properties": {
+		"engine": {"type": "string", "default": "postgres"},
+		"tier": {"allOf": [{"$ref": "#/$defs/tier"}]},
+		"tags": {"type": "array", "items": {"type": "string"}, "default": ["managed"]},
+		"rules": {"type": "array", "items": {"$ref": "#/$defs/rule"}}
+	},
+	"allOf": [
+		{
+			"properties": {
+				"replicas": {"type": "integer", "default": 1}
+			}
+		}
+	],
+	"if": {
+		"properties": {"engine": {"const": "postgres"}}
+	},
+	"then": {
+		"properties": {"port": {"type": "integer", "default": 5432}}
+	},
+	"else": {
+		"properties": {"port": {"type": "integer", "default": 3306}}
+	}
+}`)
+
+func TestInjectNestedDefaults(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  map[string]any
+		output map[string]any
+	}{
+		{
+			desc:  "Inject Defaults - Empty Input",
+			input: map[string]any{},
+			output: map[string]any{
+				"engine":   "postgres",
+				"tier":     "standard",
+				"tags":     []any{"managed"},
+				"replicas": float64(1),
+				"port":     float64(5432),
+			},
+		},
+		{
+			desc: "Inject Defaults - Else Branch and Array Items",
+			input: map[string]any{
+				"engine": "mysql",
+				"rules": []any{
+					map[string]any{},
+					map[string]any{"action": "deny"},
+				},
+			},
+			output: map[string]any{
+				"engine":   "mysql",
+				"tier":     "standard",
+				"tags":     []any{"managed"},
+				"replicas": float64(1),
+				"port":     float64(3306),
+				"rules": []any{
+					map[string]any{"action": "allow"},
+					map[string]any{"action": "deny"},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schema := MustParseJSONSchema(nestedDefaultsSchema)
+			schema.injectDefaults(tc.input)
+
+			assert.Equal(t, tc.output, tc.input)
+		})
+	}
+}
+
+func TestInjectDefaultsAreCopied(t *testing.T) {
+	schema := MustParseJSONSchema(nestedDefaultsSchema)
+
+	first := map[string]any{}
+	schema.injectDefaults(first)
+	first["tags"].([]any)[0] = "modified"
+
+	second := map[string]any{}
+	schema.injectDefaults(second)
+	assert.Equal(t, []any{"managed"}, second["tags"])
+}
+
+func TestCoerceTypes(t *testing.T) {
+	schema := MustParseJSONSchema([]byte(`{
+		"properties": {
+			"replicas": {"type": "integer"},
+			"ratio": {"type": "number"},
+			"enabled": {"type": "boolean"},
+			"name": {"type": "string"},
+			"version": {"type": "string"},
+			"invalid": {"type": "integer"},
+			"already": {"type": "number"}
+		}
+	}`))
+
+	input := map[string]any{
+		"replicas": "5",
+		"ratio":    "0.5",
+		"enabled":  "true",
+		"name":     float64(42),
+		"version":  true,
+		"invalid":  "five",
+		"already":  float64(3),
+		"unknown":  "7",
+	}
+	schema.coerceTypes(input)
+
+	assert.Equal(t, map[string]any{
+		"replicas": float64(5),
+		"ratio":    0.5,
+		"enabled":  true,
+		"name":     "42",
+		"version":  "true",
+		"invalid":  "five",
+		"already":  float64(3),
+		"unknown":  "7",
+	}, input)
+	require.NoError(t, schema.Validate(map[string]any{"replicas": input["replicas"]}))
+}
+
 func TestValidateJSONSchema(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -318,3 +486,79 @@ func TestValidateJSONSchema(t *testing.T) {
 		})
 	}
 }
+
+var annotatedSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"name": {"type": "string", "x-tempest-order": 0, "x-tempest-immutable": true},
+		"api_key": {"type": "string", "x-tempest-secret": true, "x-tempest-order": 2},
+		"notes": {"type": ["string", "null"], "x-tempest-multiline": true},
+		"region": {"type": "string", "x-tempest-options-source": "regions", "x-tempest-order": 1},
+		"size": {"type": "integer"}
+	}
+}`)
+
+func TestJSONSchemaAnnotations(t *testing.T) {
+	for _, schema := range [][]byte{
+		annotatedSchema,
+		[]byte(strings.Replace(string(annotatedSchema), "http://json-schema.org/draft-07/schema#", "https://json-schema.org/draft/2020-12/schema", 1)),
+	} {
+		s, err := ParseJSONSchema(schema)
+		require.NoError(t, err)
+
+		order := 0
+		assert.Equal(t, Annotations{Order: &order, Immutable: true}, s.Annotations("name"))
+		assert.Equal(t, Annotations{Multiline: true}, s.Annotations("notes"))
+		assert.Equal(t, "regions", s.Annotations("region").OptionsSource)
+		assert.Equal(t, Annotations{}, s.Annotations("size"))
+		assert.Equal(t, Annotations{}, s.Annotations("missing"))
+
+		assert.Equal(t, []string{"api_key"}, s.SecretProperties())
+		assert.Equal(t, []string{"name"}, s.ImmutableProperties())
+		assert.Equal(t, []string{"name", "region", "api_key", "notes", "size"}, s.PropertyOrder())
+		assert.True(t, s.isSecretProperty("api_key"))
+	}
+}
+
+func TestJSONSchemaAnnotationsInvalid(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		property string
+		err      string
+	}{
+		{
+			desc:     "ERR - Secret not a boolean",
+			property: `{"type": "string", "x-tempest-secret": "yes"}`,
+			err:      "x-tempest-secret must be a boolean, got yes",
+		},
+		{
+			desc:     "ERR - Multiline not a string",
+			property: `{"type": "integer", "x-tempest-multiline": true}`,
+			err:      "x-tempest-multiline can only be used on properties of type 'string'",
+		},
+		{
+			desc:     "ERR - Negative order",
+			property: `{"type": "string", "x-tempest-order": -1}`,
+			err:      "x-tempest-order must be a non-negative integer, got -1",
+		},
+		{
+			desc:     "ERR - Fractional order",
+			property: `{"type": "string", "x-tempest-order": 1.5}`,
+			err:      "x-tempest-order must be a non-negative integer, got 1.5",
+		},
+		{
+			desc:     "ERR - Empty options source",
+			property: `{"type": "string", "x-tempest-options-source": ""}`,
+			err:      "x-tempest-options-source must be a non-empty string, got ",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := ParseJSONSchema([]byte(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"properties": {"field": ` + tc.property + `}
+			}`))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
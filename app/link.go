@@ -1,6 +1,13 @@
 package app
 
-import appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+import (
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=LinkType -linecomment
 type LinkType int
@@ -43,3 +50,122 @@ func linkFromProto(l *appv1.Link) *Link {
 		Type:  LinkType(l.GetType()),
 	}
 }
+
+// LinkTemplate is a Link whose URL and Title contain resource variables in the format
+// of {{ resource.<property name> }}, with the same syntax as ResourceDefinition.InstructionsMarkdown.
+//
+// Besides the resource properties, the variables resource.external_id and resource.display_name
+// resolve to the Resource's ExternalID and DisplayName, unless a property with that name exists.
+// Values substituted into the URL are escaped for the component of the URL they are in:
+//   - a variable at the start of the URL is substituted as is, so that a property can hold a whole URL
+//   - in the path, the value is path escaped, slashes included, so that it stays a single segment,
+//     unless the property is one of the PathProperties
+//   - in the query, the value is query escaped, so that it can't add parameters
+//   - in the fragment, the value is path escaped
+//
+// Expanded URLs that are not absolute http or https URLs, or whose path has . or .. segments, are skipped.
+type LinkTemplate struct {
+	URL   string
+	Title string
+	Type  LinkType
+	// PathProperties are the properties whose values are paths, such as team/app. Substituted into
+	// the path of the URL, every segment of their value is path escaped and the slashes are kept.
+	PathProperties []string
+}
+
+// expand returns the Link for the given resource.
+// It returns false if any of the variables in the template could not be resolved.
+func (lt *LinkTemplate) expand(r *Resource) (*Link, bool) {
+	properties := linkTemplateProperties(r)
+
+	u, unknown := renderTemplate(lt.URL, properties, lt.escapeValue)
+	if len(unknown) > 0 {
+		return nil, false
+	}
+	if validateLinkURL(u) != nil || hasDotSegment(u) {
+		return nil, false
+	}
+
+	title, unknown := renderTemplate(lt.Title, properties, nil)
+	if len(unknown) > 0 {
+		return nil, false
+	}
+
+	return &Link{
+		URL:   u,
+		Title: title,
+		Type:  lt.Type,
+	}, true
+}
+
+// escapeValue escapes a value substituted into the URL, after the already rendered prefix.
+func (lt *LinkTemplate) escapeValue(prefix, property, v string) string {
+	switch {
+	case prefix == "":
+		return v
+	case strings.Contains(prefix, "#"):
+		return url.PathEscape(v)
+	case strings.Contains(prefix, "?"):
+		return url.QueryEscape(v)
+	case property != "" && slices.Contains(lt.PathProperties, property):
+		segments := strings.Split(v, "/")
+		for i, seg := range segments {
+			segments[i] = url.PathEscape(seg)
+		}
+		return strings.Join(segments, "/")
+	default:
+		return url.PathEscape(v)
+	}
+}
+
+// hasDotSegment reports whether the path of the URL has a . or .. segment, escaped or not, that
+// browsers resolve to another path.
+func hasDotSegment(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	for _, seg := range strings.Split(u.EscapedPath(), "/") {
+		seg, err := url.PathUnescape(seg)
+		if err != nil {
+			continue
+		}
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+
+	return false
+}
+
+// linkTemplateProperties returns the variables available to a LinkTemplate for the given resource.
+func linkTemplateProperties(r *Resource) map[string]any {
+	properties := make(map[string]any, len(r.Properties)+2)
+	properties["external_id"] = r.ExternalID
+	properties["display_name"] = r.DisplayName
+	maps.Copy(properties, r.Properties)
+
+	return properties
+}
+
+// expandLinks appends the Links expanded from the ResourceDefinition's LinkTemplates to the resource.
+// Templates with unresolved variables, and Links with a URL already present on the resource, are skipped.
+func (rd *ResourceDefinition) expandLinks(r *Resource) {
+	if r == nil {
+		return
+	}
+
+	for _, lt := range rd.LinkTemplates {
+		l, ok := lt.expand(r)
+		if !ok {
+			continue
+		}
+
+		if slices.ContainsFunc(r.Links, func(existing *Link) bool { return existing != nil && existing.URL == l.URL }) {
+			continue
+		}
+
+		r.Links = append(r.Links, l)
+	}
+}
@@ -70,3 +70,191 @@ func TestLinkFromProto(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandLinks(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		templates []LinkTemplate
+		resource  *Resource
+		want      []*Link
+	}{
+		{
+			desc: "OK - Properties and escaping",
+			templates: []LinkTemplate{
+				{
+					URL:   "https://console.example.com/buckets/{{ resource.name }}",
+					Title: "{{ resource.name }} in {{ resource.region | \"default region\" }}",
+					Type:  LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				ExternalID: "bucket-1",
+				Properties: map[string]any{
+					"name": "my bucket",
+				},
+			},
+			want: []*Link{
+				{
+					URL:   "https://console.example.com/buckets/my%20bucket",
+					Title: "my bucket in default region",
+					Type:  LinkTypeExternal,
+				},
+			},
+		},
+		{
+			desc: "OK - Query values can't add parameters",
+			templates: []LinkTemplate{
+				{
+					URL:  "https://logs.example.com/search?q={{ resource.name }}&limit=10#{{ resource.name }}",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				Properties: map[string]any{
+					"name": "a&admin=true+b c",
+				},
+			},
+			want: []*Link{
+				{
+					URL:  "https://logs.example.com/search?q=a%26admin%3Dtrue%2Bb+c&limit=10#a&admin=true+b%20c",
+					Type: LinkTypeExternal,
+				},
+			},
+		},
+		{
+			desc: "OK - Whole URL and path properties",
+			templates: []LinkTemplate{
+				{
+					URL:            "{{ resource.console_url }}/projects/{{ resource.path }}",
+					Type:           LinkTypeExternal,
+					PathProperties: []string{"path"},
+				},
+			},
+			resource: &Resource{
+				Properties: map[string]any{
+					"console_url": "https://console.example.com/org",
+					"path":        "team a/app",
+				},
+			},
+			want: []*Link{
+				{
+					URL:  "https://console.example.com/org/projects/team%20a/app",
+					Type: LinkTypeExternal,
+				},
+			},
+		},
+		{
+			desc: "OK - Slashes escaped outside path properties",
+			templates: []LinkTemplate{
+				{
+					URL:  "https://console.example.com/buckets/{{ resource.name }}/settings",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				Properties: map[string]any{
+					"name": "../../admin",
+				},
+			},
+			want: []*Link{
+				{
+					URL:  "https://console.example.com/buckets/..%2F..%2Fadmin/settings",
+					Type: LinkTypeExternal,
+				},
+			},
+		},
+		{
+			desc: "OK - Path traversal is skipped",
+			templates: []LinkTemplate{
+				{
+					URL:            "https://console.example.com/projects/{{ resource.path }}",
+					Type:           LinkTypeExternal,
+					PathProperties: []string{"path"},
+				},
+				{
+					URL:  "https://console.example.com/buckets/{{ resource.name }}/settings",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				Properties: map[string]any{
+					"path": "a/../../x",
+					"name": "..",
+				},
+			},
+		},
+		{
+			desc: "OK - Invalid URLs are skipped",
+			templates: []LinkTemplate{
+				{
+					URL:  "{{ resource.console_url }}",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				Properties: map[string]any{
+					"console_url": "javascript:alert(1)",
+				},
+			},
+		},
+		{
+			desc: "OK - Property shadows external ID",
+			templates: []LinkTemplate{
+				{
+					URL:  "https://console.example.com/{{ resource.external_id }}",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				ExternalID: "bucket-1",
+				Properties: map[string]any{
+					"external_id": "arn-1",
+				},
+			},
+			want: []*Link{
+				{
+					URL:  "https://console.example.com/arn-1",
+					Type: LinkTypeExternal,
+				},
+			},
+		},
+		{
+			desc: "OK - Unresolved and duplicate links are skipped",
+			templates: []LinkTemplate{
+				{
+					URL:  "https://console.example.com/{{ resource.missing }}",
+					Type: LinkTypeExternal,
+				},
+				{
+					URL:  "https://console.example.com/{{ resource.external_id }}",
+					Type: LinkTypeExternal,
+				},
+			},
+			resource: &Resource{
+				ExternalID: "bucket-1",
+				Links: []*Link{
+					{
+						URL:  "https://console.example.com/bucket-1",
+						Type: LinkTypeAdministration,
+					},
+				},
+			},
+			want: []*Link{
+				{
+					URL:  "https://console.example.com/bucket-1",
+					Type: LinkTypeAdministration,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rd := &ResourceDefinition{
+				LinkTemplates: tc.templates,
+			}
+
+			rd.expandLinks(tc.resource)
+			assert.Equal(t, tc.want, tc.resource.Links)
+		})
+	}
+}
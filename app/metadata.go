@@ -33,6 +33,24 @@ func metadataFromProto(m *appv1.Metadata) *Metadata {
 	}
 }
 
+func metadataToProto(m *Metadata) *appv1.Metadata {
+	if m == nil {
+		return nil
+	}
+
+	owners := make([]*appv1.Owner, 0, len(m.Owners))
+	for _, o := range m.Owners {
+		owners = append(owners, ownerToProto(o))
+	}
+
+	return &appv1.Metadata{
+		ProjectId:   m.ProjectID,
+		ProjectName: m.ProjectName,
+		Owners:      owners,
+		Author:      ownerToProto(m.Author),
+	}
+}
+
 type OwnerType string
 
 const (
@@ -65,3 +83,19 @@ func ownerFromProto(o *appv1.Owner) Owner {
 		Type:  t,
 	}
 }
+
+func ownerToProto(o Owner) *appv1.Owner {
+	var t appv1.OwnerType
+	switch o.Type {
+	case OwnerTypeUser:
+		t = appv1.OwnerType_OWNER_TYPE_USER
+	case OwnerTypeTeam:
+		t = appv1.OwnerType_OWNER_TYPE_TEAM
+	}
+
+	return &appv1.Owner{
+		Email: o.Email,
+		Name:  o.Name,
+		Type:  t,
+	}
+}
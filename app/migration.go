@@ -0,0 +1,150 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SchemaVersionProperty is the reserved property used to stamp the SchemaVersion on resources
+// sent to Tempest, as the Resource message has no field to carry it. It is removed from the properties
+// of incoming resources before handlers see them, so a PropertiesSchema must not define it.
+// When the SchemaVersion is set, Describe declares it in the PropertiesSchema, so that the stamped
+// properties are valid even if the schema disallows additional properties.
+const SchemaVersionProperty = "_schema_version"
+
+// MigrationFunc upgrades resource properties by one schema version.
+// It receives a copy of the properties and returns the upgraded properties.
+type MigrationFunc func(properties map[string]any) (map[string]any, error)
+
+// migration upgrades resource properties from one schema version to the next.
+type migration struct {
+	from int
+	fn   MigrationFunc
+}
+
+// AddMigration adds a MigrationFunc that upgrades resource properties from the fromVersion
+// schema version to fromVersion+1.
+//
+// Before handlers are called, the properties of incoming resources are upgraded from the version
+// they were stamped with to the ResourceDefinition's SchemaVersion, by running every migration in order.
+// Resources without a version stamp are at version 0. Versions without a registered migration
+// leave the properties unchanged.
+//
+// Resources returned by handlers, including the resources of a List operation, are stamped with the
+// current SchemaVersion and are not migrated: they must conform to the current PropertiesSchema.
+func (rd *ResourceDefinition) AddMigration(fromVersion int, fn MigrationFunc) {
+	if err := rd.addMigration(fromVersion, fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) addMigration(fromVersion int, fn MigrationFunc) error {
+	if fn == nil {
+		return errors.New("MigrationFunc must be set for a Migration")
+	}
+
+	if fromVersion < 0 {
+		return fmt.Errorf("migration version must not be negative, got %d", fromVersion)
+	}
+
+	for _, existing := range rd.migrations {
+		if existing.from == fromVersion {
+			return fmt.Errorf("migration from version %d already exists", fromVersion)
+		}
+	}
+
+	rd.migrations = append(rd.migrations, migration{from: fromVersion, fn: fn})
+	return nil
+}
+
+// migrate upgrades the properties of the resource to the ResourceDefinition's SchemaVersion.
+func (rd *ResourceDefinition) migrate(r *Resource) error {
+	if r == nil || r.SchemaVersion == rd.SchemaVersion {
+		return nil
+	}
+
+	// Resources without properties, such as the resource of a create operation, have nothing to migrate.
+	if len(r.Properties) == 0 {
+		r.SchemaVersion = rd.SchemaVersion
+		return nil
+	}
+
+	if r.SchemaVersion > rd.SchemaVersion {
+		return fmt.Errorf("resource schema version %d is newer than the supported version %d", r.SchemaVersion, rd.SchemaVersion)
+	}
+
+	properties := r.Properties
+	for v := r.SchemaVersion; v < rd.SchemaVersion; v++ {
+		for _, m := range rd.migrations {
+			if m.from != v {
+				continue
+			}
+
+			migrated, err := m.fn(maps.Clone(properties))
+			if err != nil {
+				return fmt.Errorf("migrate from version %d: %w", v, err)
+			}
+			properties = migrated
+		}
+	}
+
+	r.Properties = properties
+	r.SchemaVersion = rd.SchemaVersion
+	return nil
+}
+
+// finalizeResource prepares a resource returned by a handler to be sent to Tempest,
+// by expanding the LinkTemplates and stamping the current SchemaVersion.
+func (rd *ResourceDefinition) finalizeResource(r *Resource) {
+	rd.expandLinks(r)
+	r.SchemaVersion = rd.SchemaVersion
+}
+
+// schemaVersionFromProperties removes the SchemaVersionProperty from the properties and returns its value.
+func schemaVersionFromProperties(properties map[string]any) int {
+	v, ok := properties[SchemaVersionProperty]
+	if !ok {
+		return 0
+	}
+	delete(properties, SchemaVersionProperty)
+
+	f, ok := v.(float64)
+	if !ok {
+		return 0
+	}
+
+	return int(f)
+}
+
+// propertiesSchemaToStruct converts the PropertiesSchema to a struct for Describe, and declares the
+// SchemaVersionProperty when the SchemaVersion is set.
+func (rd *ResourceDefinition) propertiesSchemaToStruct() (*structpb.Struct, error) {
+	st, err := rd.PropertiesSchema.toStruct()
+	if err != nil {
+		return nil, err
+	}
+
+	if rd.SchemaVersion == 0 {
+		return st, nil
+	}
+
+	properties := st.GetFields()["properties"].GetStructValue()
+	if properties == nil {
+		properties = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		st.Fields["properties"] = structpb.NewStructValue(properties)
+	}
+
+	properties.Fields[SchemaVersionProperty] = structpb.NewStructValue(&structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"type":        structpb.NewStringValue("integer"),
+			"minimum":     structpb.NewNumberValue(0),
+			"readOnly":    structpb.NewBoolValue(true),
+			"description": structpb.NewStringValue("The version of the schema that the properties conform to."),
+		},
+	})
+
+	return st, nil
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+// renameProperty returns a MigrationFunc that renames a property.
+func renameProperty(from, to string) MigrationFunc {
+	return func(properties map[string]any) (map[string]any, error) {
+		if v, ok := properties[from]; ok {
+			properties[to] = v
+			delete(properties, from)
+		}
+		return properties, nil
+	}
+}
+
+func TestAddMigration(t *testing.T) {
+	rd := ResourceDefinition{}
+	rd.AddMigration(0, renameProperty("name", "title"))
+
+	assert.PanicsWithValue(t, "migration from version 0 already exists", func() {
+		rd.AddMigration(0, renameProperty("name", "title"))
+	})
+	assert.PanicsWithValue(t, "MigrationFunc must be set for a Migration", func() {
+		rd.AddMigration(1, nil)
+	})
+	assert.PanicsWithValue(t, "migration version must not be negative, got -1", func() {
+		rd.AddMigration(-1, renameProperty("name", "title"))
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	rd := ResourceDefinition{SchemaVersion: 3}
+	rd.AddMigration(1, renameProperty("title", "label"))
+	rd.AddMigration(0, renameProperty("name", "title"))
+
+	testCases := []struct {
+		desc     string
+		resource *Resource
+		expected *Resource
+		err      string
+	}{
+		{
+			desc: "OK - Unversioned",
+			resource: &Resource{
+				Properties: map[string]any{"name": "example"},
+			},
+			expected: &Resource{
+				Properties:    map[string]any{"label": "example"},
+				SchemaVersion: 3,
+			},
+		},
+		{
+			desc: "OK - Partially Migrated",
+			resource: &Resource{
+				Properties:    map[string]any{"title": "example"},
+				SchemaVersion: 1,
+			},
+			expected: &Resource{
+				Properties:    map[string]any{"label": "example"},
+				SchemaVersion: 3,
+			},
+		},
+		{
+			desc: "OK - Current Version",
+			resource: &Resource{
+				Properties:    map[string]any{"label": "example"},
+				SchemaVersion: 3,
+			},
+			expected: &Resource{
+				Properties:    map[string]any{"label": "example"},
+				SchemaVersion: 3,
+			},
+		},
+		{
+			desc:     "OK - No Properties",
+			resource: &Resource{},
+			expected: &Resource{SchemaVersion: 3},
+		},
+		{
+			desc: "ERR - Newer Version",
+			resource: &Resource{
+				Properties:    map[string]any{"label": "example"},
+				SchemaVersion: 4,
+			},
+			err: "resource schema version 4 is newer than the supported version 3",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := rd.migrate(tc.resource)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, tc.resource)
+		})
+	}
+}
+
+func TestMigrateError(t *testing.T) {
+	rd := ResourceDefinition{SchemaVersion: 1}
+	rd.AddMigration(0, func(map[string]any) (map[string]any, error) {
+		return nil, errors.New("boom")
+	})
+
+	properties := map[string]any{"name": "example"}
+	err := rd.migrate(&Resource{Properties: properties})
+	assert.EqualError(t, err, "migrate from version 0: boom")
+	assert.Equal(t, map[string]any{"name": "example"}, properties)
+}
+
+func TestExecuteResourceOperationMigration(t *testing.T) {
+	schema := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"title": {"type": "string"}
+		}
+	}`))
+
+	var seen map[string]any
+	rd := generateRD(nil)
+	rd.PropertiesSchema = schema
+	rd.SchemaVersion = 1
+	rd.AddMigration(0, renameProperty("name", "title"))
+	rd.ReadFn(func(_ context.Context, req *OperationRequest) (*OperationResponse, error) {
+		seen = req.Resource.Properties
+		return &OperationResponse{
+			Resource: &Resource{
+				ExternalID: req.Resource.ExternalID,
+				Properties: req.Resource.Properties,
+			},
+		}, nil
+	})
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	res, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource: &appv1.Resource{
+			Type:       "example",
+			ExternalId: "123",
+			Properties: mustNewStruct(map[string]any{"name": "example"}),
+		},
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"title": "example"}, seen)
+	assert.Equal(t, map[string]any{
+		"title":               "example",
+		SchemaVersionProperty: float64(1),
+	}, res.Msg.Resource.Properties.AsMap())
+
+	_, err = app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource: &appv1.Resource{
+			Type:       "example",
+			ExternalId: "123",
+			Properties: mustNewStruct(map[string]any{"title": "example", SchemaVersionProperty: 2}),
+		},
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
+	}))
+	assert.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+}
+
+func TestListResourcesMigratesResource(t *testing.T) {
+	schema := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"title": {"type": "string"}
+		}
+	}`))
+
+	var seen map[string]any
+	rd := generateRD(nil)
+	rd.PropertiesSchema = schema
+	rd.SchemaVersion = 1
+	rd.AddMigration(0, renameProperty("name", "title"))
+	rd.ListFn(func(_ context.Context, req *ListRequest) (*ListResponse, error) {
+		seen = req.Resource.Properties
+		return &ListResponse{
+			Resources: []*Resource{{ExternalID: "123", Properties: map[string]any{"title": "example"}}},
+		}, nil
+	})
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	res, err := app.ListResources(context.Background(), connect.NewRequest(&appv1.ListResourcesRequest{
+		Resource: &appv1.Resource{
+			Type:       "example",
+			Properties: mustNewStruct(map[string]any{"name": "example"}),
+		},
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"title": "example"}, seen)
+	require.Len(t, res.Msg.Resources, 1)
+	assert.Equal(t, map[string]any{
+		"title":               "example",
+		SchemaVersionProperty: float64(1),
+	}, res.Msg.Resources[0].Properties.AsMap())
+}
+
+func TestDescribeDeclaresSchemaVersion(t *testing.T) {
+	schema := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"title": {"type": "string"}
+		},
+		"additionalProperties": false
+	}`))
+
+	rd := generateRD(nil)
+	rd.PropertiesSchema = schema
+	rd.SchemaVersion = 2
+	rd.ReadFn(func(_ context.Context, req *OperationRequest) (*OperationResponse, error) {
+		return &OperationResponse{
+			Resource: &Resource{
+				ExternalID: req.Resource.ExternalID,
+				Properties: map[string]any{"title": "example"},
+			},
+		}, nil
+	})
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	describe, err := app.Describe(context.Background(), connect.NewRequest(&appv1.DescribeRequest{}))
+	require.NoError(t, err)
+	require.Len(t, describe.Msg.ResourceDefinitions, 1)
+
+	raw, err := describe.Msg.ResourceDefinitions[0].PropertiesSchema.MarshalJSON()
+	require.NoError(t, err)
+	described, err := ParseJSONSchema(raw)
+	require.NoError(t, err)
+
+	res, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource: &appv1.Resource{
+			Type:       "example",
+			ExternalId: "123",
+		},
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
+	}))
+	require.NoError(t, err)
+
+	properties := res.Msg.Resource.Properties.AsMap()
+	assert.Equal(t, float64(2), properties[SchemaVersionProperty])
+	assert.NoError(t, described.Validate(properties))
+
+	// The schema of the ResourceDefinition itself is not changed.
+	assert.Nil(t, rd.PropertiesSchema.Properties[SchemaVersionProperty])
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 
 	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
 )
@@ -22,6 +23,11 @@ type EnvironmentVariable struct {
 	Type  EnvironmentVariableType
 }
 
+// ErrNotFound is returned, optionally wrapped, by OperationFuncs, ListFuncs and action Handlers when the
+// resource does not exist in the external system, for example when it is read after being deleted.
+// The request then fails with connect.CodeNotFound instead of connect.CodeInternal.
+var ErrNotFound = errors.New("resource not found")
+
 // OperationRequest contains the input data for an operation on a resource.
 type OperationRequest struct {
 	// Metadata contains information about the Project and User making the request.
@@ -82,8 +88,9 @@ type OperationResponse struct {
 // operation is a struct that contains the schema and function for an operation.
 // This must be constructed using the appropriate methods on the ResourceDefinition.
 type operation struct {
-	schema schema
-	fn     OperationFunc
+	schema   schema
+	fn       OperationFunc
+	validate ValidateFunc
 }
 
 // schema contains the input and output JSON schemas for an operation.
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Option is an allowed value for an input property.
+type Option struct {
+	// Value is the value set on the input property when the Option is selected.
+	Value any
+	// Label is the text shown to users for the Option. The Value is shown when it is empty.
+	Label string
+}
+
+// OptionsRequest contains the data available to an OptionsFunc.
+type OptionsRequest struct {
+	// Metadata contains information about the Project and User making the request.
+	Metadata *Metadata
+	// Property is the name of the input property that options are requested for.
+	Property string
+	// Input contains the values the user has entered so far. It is partial and has not been validated,
+	// but can be used to narrow the options, for example to list the subnets of the selected VPC.
+	Input map[string]any
+	// Environment contains the environment variables that are available to the operation.
+	Environment map[string]EnvironmentVariable
+}
+
+// OptionsResponse contains the allowed values for an input property.
+type OptionsResponse struct {
+	Options []Option
+}
+
+// OptionsFunc returns the allowed values for an input property, fetched from the external system.
+type OptionsFunc func(context.Context, *OptionsRequest) (*OptionsResponse, error)
+
+// AddOptionsFunc adds an OptionsFunc that provides the allowed values of the named create or update
+// input property at the time the form is filled in. The property is advertised in Describe with the
+// x-tempest-options-source annotation, and the options are fetched through App.Options.
+func (rd *ResourceDefinition) AddOptionsFunc(property string, fn OptionsFunc) {
+	if err := rd.addOptionsFunc(property, fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) addOptionsFunc(property string, fn OptionsFunc) error {
+	if property == "" {
+		return errors.New("property name is required for an OptionsFunc")
+	}
+
+	if fn == nil {
+		return errors.New("OptionsFunc must be set")
+	}
+
+	if _, ok := rd.options[property]; ok {
+		return fmt.Errorf("OptionsFunc for property '%s' already exists", property)
+	}
+
+	if rd.options == nil {
+		rd.options = make(map[string]OptionsFunc)
+	}
+	rd.options[property] = fn
+
+	return nil
+}
+
+// inputSchemaToStruct converts an input schema to a struct for Describe, and annotates
+// the properties that have an OptionsFunc with x-tempest-options-source.
+func (rd *ResourceDefinition) inputSchemaToStruct(s *JSONSchema) (*structpb.Struct, error) {
+	st, err := s.toStruct()
+	if err != nil {
+		return nil, err
+	}
+
+	properties := st.GetFields()["properties"].GetStructValue()
+	for name := range rd.options {
+		p := properties.GetFields()[name].GetStructValue()
+		if p == nil {
+			continue
+		}
+
+		p.Fields[AnnotationOptionsSource] = structpb.NewStringValue(name)
+	}
+
+	return st, nil
+}
+
+// Options returns the allowed values of an input property of the resource type,
+// by calling the OptionsFunc added for the property.
+func (a *App) Options(ctx context.Context, resourceType string, req *OptionsRequest) (*OptionsResponse, error) {
+	if req == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("options request is required"))
+	}
+
+	rd, ok := a.getResourceDefinition(resourceType)
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("resource type %s not found", resourceType))
+	}
+
+	fn, ok := rd.options[req.Property]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("options not supported for property %s of resource type %s", req.Property, resourceType))
+	}
+
+	// Copy the input so that the OptionsFunc can't modify the caller's values.
+	r := *req
+	r.Input = maps.Clone(req.Input)
+	if r.Input == nil {
+		r.Input = map[string]any{}
+	}
+
+	res, err := fn(ctx, &r)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("get options: %w", err))
+	}
+
+	if res == nil {
+		return &OptionsResponse{}, nil
+	}
+
+	return res, nil
+}
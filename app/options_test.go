@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+var optionsInputSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"vpc": {"type": "string"},
+		"subnet": {"type": "string"}
+	}
+}`)
+
+func subnetOptions(_ context.Context, req *OptionsRequest) (*OptionsResponse, error) {
+	switch req.Input["vpc"] {
+	case "vpc-1":
+		return &OptionsResponse{
+			Options: []Option{
+				{Value: "subnet-a", Label: "Subnet A"},
+				{Value: "subnet-b", Label: "Subnet B"},
+			},
+		}, nil
+	case "broken":
+		return nil, errors.New("boom")
+	default:
+		return nil, nil
+	}
+}
+
+func TestAddOptionsFunc(t *testing.T) {
+	rd := ResourceDefinition{}
+	rd.AddOptionsFunc("subnet", subnetOptions)
+
+	assert.PanicsWithValue(t, "OptionsFunc for property 'subnet' already exists", func() {
+		rd.AddOptionsFunc("subnet", subnetOptions)
+	})
+	assert.PanicsWithValue(t, "OptionsFunc must be set", func() {
+		rd.AddOptionsFunc("vpc", nil)
+	})
+	assert.PanicsWithValue(t, "property name is required for an OptionsFunc", func() {
+		rd.AddOptionsFunc("", subnetOptions)
+	})
+}
+
+func TestAppOptions(t *testing.T) {
+	rd := generateRD(nil)
+	rd.CreateFn(simpleOpFn, MustParseJSONSchema(optionsInputSchema))
+	rd.AddOptionsFunc("subnet", subnetOptions)
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	testCases := []struct {
+		desc         string
+		resourceType string
+		req          *OptionsRequest
+		options      []Option
+		code         connect.Code
+	}{
+		{
+			desc:         "OK",
+			resourceType: "example",
+			req:          &OptionsRequest{Property: "subnet", Input: map[string]any{"vpc": "vpc-1"}},
+			options: []Option{
+				{Value: "subnet-a", Label: "Subnet A"},
+				{Value: "subnet-b", Label: "Subnet B"},
+			},
+		},
+		{
+			desc:         "OK - No Options",
+			resourceType: "example",
+			req:          &OptionsRequest{Property: "subnet"},
+		},
+		{
+			desc:         "ERR - Nil Request",
+			resourceType: "example",
+			code:         connect.CodeInvalidArgument,
+		},
+		{
+			desc:         "ERR - Unknown Resource Type",
+			resourceType: "unknown",
+			req:          &OptionsRequest{Property: "subnet"},
+			code:         connect.CodeNotFound,
+		},
+		{
+			desc:         "ERR - No OptionsFunc",
+			resourceType: "example",
+			req:          &OptionsRequest{Property: "vpc"},
+			code:         connect.CodeNotFound,
+		},
+		{
+			desc:         "ERR - OptionsFunc Error",
+			resourceType: "example",
+			req:          &OptionsRequest{Property: "subnet", Input: map[string]any{"vpc": "broken"}},
+			code:         connect.CodeInternal,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := app.Options(context.Background(), tc.resourceType, tc.req)
+			if tc.code != 0 {
+				assert.Equal(t, tc.code, connect.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.options, res.Options)
+		})
+	}
+}
+
+func TestDescribeOptionsSource(t *testing.T) {
+	rd := generateRD(nil)
+	rd.CreateFn(simpleOpFn, MustParseJSONSchema(optionsInputSchema))
+	rd.AddOptionsFunc("subnet", subnetOptions)
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	res, err := app.Describe(context.Background(), connect.NewRequest(&appv1.DescribeRequest{}))
+	require.NoError(t, err)
+
+	properties := res.Msg.ResourceDefinitions[0].CreateInputSchema.AsMap()["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", AnnotationOptionsSource: "subnet"}, properties["subnet"])
+	assert.Equal(t, map[string]any{"type": "string"}, properties["vpc"])
+}
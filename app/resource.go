@@ -19,6 +19,10 @@ type Resource struct {
 	Links []*Link
 	// Properties contains the properties of the resource. These properties are validated against the resource Properties schema.
 	Properties map[string]any
+	// SchemaVersion is the version of the ResourceDefinition's PropertiesSchema that the Properties conform to.
+	// Incoming resources are migrated to the current SchemaVersion before handlers see them, and
+	// returned resources are stamped with it.
+	SchemaVersion int
 }
 
 func (r *Resource) toProto() (*appv1.Resource, error) {
@@ -36,6 +40,10 @@ func (r *Resource) toProto() (*appv1.Resource, error) {
 		return nil, fmt.Errorf("convert properties to struct: %w", err)
 	}
 
+	if r.SchemaVersion > 0 {
+		properties.Fields[SchemaVersionProperty] = structpb.NewNumberValue(float64(r.SchemaVersion))
+	}
+
 	return &appv1.Resource{
 		ExternalId:  r.ExternalID,
 		DisplayName: r.DisplayName,
@@ -55,11 +63,14 @@ func resourceFromProto(r *appv1.Resource) *Resource {
 		links = append(links, linkFromProto(l))
 	}
 
+	properties := r.GetProperties().AsMap()
+
 	return &Resource{
-		ExternalID:  r.GetExternalId(),
-		DisplayName: r.GetDisplayName(),
-		Type:        r.GetType(),
-		Links:       links,
-		Properties:  r.GetProperties().AsMap(),
+		ExternalID:    r.GetExternalId(),
+		DisplayName:   r.GetDisplayName(),
+		Type:          r.GetType(),
+		Links:         links,
+		Properties:    properties,
+		SchemaVersion: schemaVersionFromProperties(properties),
 	}
 }
@@ -1,5 +1,7 @@
 package app
 
+import "errors"
+
 // getResourceDefinition returns the ResourceDefinition with the given type, if it exists.
 func (a *App) getResourceDefinition(t string) (*ResourceDefinition, bool) {
 	for _, rd := range a.resourceDefinitions {
@@ -25,10 +27,17 @@ type ResourceDefinition struct {
 	// Links are links to documentation or other resources that can help users
 	// understand how to use this Resource.
 	Links []Link
+	// LinkTemplates are expanded into resource-specific Links for each Resource returned
+	// by the Create, Read, Update and List operations, and appended to Resource.Links.
+	LinkTemplates []LinkTemplate
 	// Markdown formatted instructions for setting up or using the resource.
 	// This field supports resource property variables in the format of {{ resource.<property name> }}.
 	InstructionsMarkdown string
 
+	// SchemaVersion is the current version of the PropertiesSchema. It must be incremented when the
+	// properties change in a way that requires existing resources to be migrated. See AddMigration.
+	SchemaVersion int
+
 	// The CRUD operations that can be performed on this resource. These operations are optional.
 	// These operations must be added by using the appropriate methods on the ResourceDefinition.
 	create *operation
@@ -46,6 +55,14 @@ type ResourceDefinition struct {
 	// A good example of an action might be "Trigger a Build" on a CI/CD resource.
 	// Actions must be added by using the AddAction method on the ResourceDefinition.
 	actions []ActionDefinition
+
+	// migrations upgrade the properties of resources stamped with an older SchemaVersion.
+	// Migrations must be added by using the AddMigration method on the ResourceDefinition.
+	migrations []migration
+
+	// options provide the allowed values of input properties, keyed by property name.
+	// OptionsFuncs must be added by using the AddOptionsFunc method on the ResourceDefinition.
+	options map[string]OptionsFunc
 }
 
 // CreateFn adds a Create operation Handler to the ResourceDefinition.
@@ -54,16 +71,22 @@ type ResourceDefinition struct {
 // The Handler should create the resource in the external system and return the resource's ExternalID and properties.
 // See the Create operation in the Printer example for an example implementation.
 func (rd *ResourceDefinition) CreateFn(fn OperationFunc, inputSchema *JSONSchema) {
+	if err := rd.setCreate(fn, inputSchema); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setCreate(fn OperationFunc, inputSchema *JSONSchema) error {
 	if inputSchema == nil {
-		panic("input schema is nil")
+		return errors.New("input schema is nil")
 	}
 
 	if rd.PropertiesSchema == nil {
-		panic("Properties must be set before adding a Create Operation")
+		return errors.New("properties schema must be set before adding a Create Operation")
 	}
 
 	if fn == nil {
-		panic("OperationFunc must be set for a Create Operation")
+		return errors.New("OperationFunc must be set for a Create Operation")
 	}
 
 	rd.create = &operation{
@@ -73,6 +96,7 @@ func (rd *ResourceDefinition) CreateFn(fn OperationFunc, inputSchema *JSONSchema
 		},
 		fn: fn,
 	}
+	return nil
 }
 
 // UpdateFn adds an Update operation Handler to the ResourceDefinition.
@@ -81,16 +105,22 @@ func (rd *ResourceDefinition) CreateFn(fn OperationFunc, inputSchema *JSONSchema
 // The Handler should update the resource in the external system and return the updated resource's properties.
 // See the Update operation in the Printer example for an example implementation.
 func (rd *ResourceDefinition) UpdateFn(fn OperationFunc, inputSchema *JSONSchema) {
+	if err := rd.setUpdate(fn, inputSchema); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setUpdate(fn OperationFunc, inputSchema *JSONSchema) error {
 	if inputSchema == nil {
-		panic("input schema is nil")
+		return errors.New("input schema is nil")
 	}
 
 	if rd.PropertiesSchema == nil {
-		panic("properties schema must be set before adding an Update Operation")
+		return errors.New("properties schema must be set before adding an Update Operation")
 	}
 
 	if fn == nil {
-		panic("OperationFunc must be set for an Update Operation")
+		return errors.New("OperationFunc must be set for an Update Operation")
 	}
 
 	rd.update = &operation{
@@ -100,6 +130,7 @@ func (rd *ResourceDefinition) UpdateFn(fn OperationFunc, inputSchema *JSONSchema
 		},
 		fn: fn,
 	}
+	return nil
 }
 
 // DeleteFn adds a Delete operation Handler to the ResourceDefinition.
@@ -107,12 +138,18 @@ func (rd *ResourceDefinition) UpdateFn(fn OperationFunc, inputSchema *JSONSchema
 // The Handler should delete the resource in the external system.
 // See the Delete operation in the Printer example for an example implementation.
 func (rd *ResourceDefinition) DeleteFn(fn OperationFunc) {
+	if err := rd.setDelete(fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setDelete(fn OperationFunc) error {
 	if rd.PropertiesSchema == nil {
-		panic("Properties must be set before adding a Delete handler")
+		return errors.New("properties schema must be set before adding a Delete handler")
 	}
 
 	if fn == nil {
-		panic("OperationFunc must be set for a Delete Operation")
+		return errors.New("OperationFunc must be set for a Delete Operation")
 	}
 
 	rd.delete = &operation{
@@ -122,6 +159,7 @@ func (rd *ResourceDefinition) DeleteFn(fn OperationFunc) {
 		},
 		fn: fn,
 	}
+	return nil
 }
 
 // ReadFn adds a Read Operation to the ResourceDefinition.
@@ -129,12 +167,18 @@ func (rd *ResourceDefinition) DeleteFn(fn OperationFunc) {
 // The Handler should query the external system for the resource's current state and return it.
 // See the Read operation in the Printer example for an example implementation.
 func (rd *ResourceDefinition) ReadFn(fn OperationFunc) {
+	if err := rd.setRead(fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setRead(fn OperationFunc) error {
 	if rd.PropertiesSchema == nil {
-		panic("Properties must be set before adding a Read handler")
+		return errors.New("properties schema must be set before adding a Read handler")
 	}
 
 	if fn == nil {
-		panic("OperationFunc must be set for a Read Operation")
+		return errors.New("OperationFunc must be set for a Read Operation")
 	}
 
 	rd.read = &operation{
@@ -144,6 +188,7 @@ func (rd *ResourceDefinition) ReadFn(fn OperationFunc) {
 		},
 		fn: fn,
 	}
+	return nil
 }
 
 // ListFn adds a List Handler to the ResourceDefinition.
@@ -152,12 +197,18 @@ func (rd *ResourceDefinition) ReadFn(fn OperationFunc) {
 // The Handler should query the external system for all resources of this type and return them.
 // See the List operation in the Printer example for an example implementation.
 func (rd *ResourceDefinition) ListFn(fn ListFunc) {
+	if err := rd.setList(fn); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (rd *ResourceDefinition) setList(fn ListFunc) error {
 	if rd.PropertiesSchema == nil {
-		panic("Properties must be set before adding a List handler")
+		return errors.New("properties schema must be set before adding a List handler")
 	}
 
 	if fn == nil {
-		panic("ListFunc must be set for a List Operation")
+		return errors.New("ListFunc must be set for a List Operation")
 	}
 
 	rd.list = &listOperation{
@@ -167,6 +218,7 @@ func (rd *ResourceDefinition) ListFn(fn ListFunc) {
 		},
 		fn: fn,
 	}
+	return nil
 }
 
 // HealthCheckFn adds a HealthCheck Handler to the ResourceDefinition.
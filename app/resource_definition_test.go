@@ -70,6 +70,10 @@ var (
 	simpleListFn = func(_ context.Context, _ *ListRequest) (*ListResponse, error) {
 		return &ListResponse{}, nil
 	}
+
+	simpleValidateFn = func(_ context.Context, _ *OperationRequest) error {
+		return nil
+	}
 )
 
 func TestCreateFn(t *testing.T) {
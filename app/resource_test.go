@@ -44,6 +44,26 @@ func TestResourceFromProto(t *testing.T) {
 				Properties: nil,
 			},
 		},
+		{
+			desc: "OK - Schema Version",
+			resource: &Resource{
+				ExternalID: "external-id",
+				Type:       "type",
+				Links:      []*Link{},
+				Properties: map[string]any{
+					"name": "example",
+				},
+				SchemaVersion: 2,
+			},
+			resourcepb: &appv1.Resource{
+				ExternalId: "external-id",
+				Type:       "type",
+				Properties: mustNewStruct(map[string]any{
+					"name":                "example",
+					SchemaVersionProperty: 2,
+				}),
+			},
+		},
 		{
 			desc:       "OK - nil",
 			resource:   nil,
@@ -95,6 +115,26 @@ func TestResourceToProto(t *testing.T) {
 				Properties: mustNewStruct(map[string]any{"key": "value"}),
 			},
 		},
+		{
+			desc: "OK - Schema Version",
+			resource: &Resource{
+				ExternalID: "external-id",
+				Type:       "type",
+				Properties: map[string]any{
+					"key": "value",
+				},
+				SchemaVersion: 3,
+			},
+			resourcepb: &appv1.Resource{
+				ExternalId: "external-id",
+				Type:       "type",
+				Links:      []*appv1.Link{},
+				Properties: mustNewStruct(map[string]any{
+					"key":                 "value",
+					SchemaVersionProperty: 3,
+				}),
+			},
+		},
 		{
 			desc:       "ERR - nil",
 			resource:   nil,
@@ -31,7 +31,7 @@ func (a *App) Describe(_ context.Context, _ *connect.Request[appv1.DescribeReque
 		}
 
 		if rd.PropertiesSchema != nil {
-			s, err := rd.PropertiesSchema.toStruct()
+			s, err := rd.propertiesSchemaToStruct()
 			if err != nil {
 				return nil, fmt.Errorf("convert properties schema to struct: %w", err)
 			}
@@ -41,7 +41,7 @@ func (a *App) Describe(_ context.Context, _ *connect.Request[appv1.DescribeReque
 		if rd.create != nil {
 			r.CreateSupported = true
 
-			s, err := rd.create.schema.input.toStruct()
+			s, err := rd.inputSchemaToStruct(rd.create.schema.input)
 			if err != nil {
 				return nil, fmt.Errorf("convert create input schema to struct: %w", err)
 			}
@@ -51,7 +51,7 @@ func (a *App) Describe(_ context.Context, _ *connect.Request[appv1.DescribeReque
 		if rd.update != nil {
 			r.UpdateSupported = true
 
-			s, err := rd.update.schema.input.toStruct()
+			s, err := rd.inputSchemaToStruct(rd.update.schema.input)
 			if err != nil {
 				return nil, fmt.Errorf("convert update input schema to struct: %w", err)
 			}
@@ -116,6 +116,11 @@ func (a *App) ExecuteResourceOperation(ctx context.Context, req *connect.Request
 
 	opReq := operationRequestFromProto(req.Msg)
 
+	// Upgrade the properties of resources stored with an older SchemaVersion before the handlers see them.
+	if err := rd.migrate(opReq.Resource); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("migrate resource properties: %w", err))
+	}
+
 	switch o := req.Msg.Operation; o {
 	case appv1.ResourceOperation_RESOURCE_OPERATION_CREATE:
 		op := operationForType(rd, o)
@@ -124,21 +129,33 @@ func (a *App) ExecuteResourceOperation(ctx context.Context, req *connect.Request
 		}
 
 		// Inject default values from the Schema into the input, then validate the input.
-		op.schema.input.injectDefaults(opReq.Input)
+		a.prepareInput(op.schema.input, opReq.Input)
 		if err := op.schema.input.Validate(opReq.Input); err != nil {
-			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("validate create input: %w", err))
+			return nil, invalidInputError("validate create input", err, op.schema.input, opReq.Input, opReq.Environment)
+		}
+
+		if op.validate != nil {
+			if err := op.validate(ctx, opReq); err != nil {
+				return nil, validationFuncError("validate create input", err, op.schema.input, opReq.Input, opReq.Environment)
+			}
 		}
 
 		res, err := op.fn(ctx, opReq)
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("create resource: %w", err))
+			return nil, handlerError("create resource", err)
+		}
+
+		if err := a.normalizeResource(res.Resource); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize create output: %w", err))
 		}
 
 		// Catch any validation errors before returning the resource.
 		if err := op.schema.output.Validate(res.Resource.Properties); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("validate create output: %w", err))
+			return nil, invalidOutputError("validate create output", err, op.schema.output, res.Resource.Properties)
 		}
 
+		rd.finalizeResource(res.Resource)
+
 		resource, err := res.Resource.toProto()
 		if err != nil {
 			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("convert resource to proto: %w", err))
@@ -157,22 +174,41 @@ func (a *App) ExecuteResourceOperation(ctx context.Context, req *connect.Request
 			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("external ID is required for update operation"))
 		}
 
+		// Keep the immutable properties omitted by the caller, so that default values don't change them.
+		rd.keepImmutable(op.schema.input, opReq)
+
 		// Inject default values from the Schema into the input, then validate the input.
-		op.schema.input.injectDefaults(opReq.Input)
+		a.prepareInput(op.schema.input, opReq.Input)
 		if err := op.schema.input.Validate(opReq.Input); err != nil {
-			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("validate update input: %w", err))
+			return nil, invalidInputError("validate update input", err, op.schema.input, opReq.Input, opReq.Environment)
+		}
+
+		if err := rd.checkImmutable(op.schema.input, opReq); err != nil {
+			return nil, err
+		}
+
+		if op.validate != nil {
+			if err := op.validate(ctx, opReq); err != nil {
+				return nil, validationFuncError("validate update input", err, op.schema.input, opReq.Input, opReq.Environment)
+			}
 		}
 
 		res, err := op.fn(ctx, opReq)
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("update resource: %w", err))
+			return nil, handlerError("update resource", err)
+		}
+
+		if err := a.normalizeResource(res.Resource); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize update output: %w", err))
 		}
 
 		// Catch any validation errors before returning the resource.
 		if err := op.schema.output.Validate(res.Resource.Properties); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("validate update output: %w", err))
+			return nil, invalidOutputError("validate update output", err, op.schema.output, res.Resource.Properties)
 		}
 
+		rd.finalizeResource(res.Resource)
+
 		resource, err := res.Resource.toProto()
 		if err != nil {
 			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("convert resource to proto: %w", err))
@@ -193,7 +229,11 @@ func (a *App) ExecuteResourceOperation(ctx context.Context, req *connect.Request
 
 		res, err := op.fn(ctx, opReq)
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("delete resource: %w", err))
+			return nil, handlerError("delete resource", err)
+		}
+
+		if err := a.normalizeResource(res.Resource); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize delete output: %w", err))
 		}
 
 		// We don't validate the output properties for a delete operation.
@@ -217,14 +257,20 @@ func (a *App) ExecuteResourceOperation(ctx context.Context, req *connect.Request
 
 		res, err := op.fn(ctx, opReq)
 		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("read resource: %w", err))
+			return nil, handlerError("read resource", err)
+		}
+
+		if err := a.normalizeResource(res.Resource); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize read output: %w", err))
 		}
 
 		// Catch any validation errors before returning the resource.
 		if err := op.schema.output.Validate(res.Resource.Properties); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("validate read output: %w", err))
+			return nil, invalidOutputError("validate read output", err, op.schema.output, res.Resource.Properties)
 		}
 
+		rd.finalizeResource(res.Resource)
+
 		resource, err := res.Resource.toProto()
 		if err != nil {
 			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("convert resource to proto: %w", err))
@@ -252,20 +298,33 @@ func (a *App) ListResources(ctx context.Context, req *connect.Request[appv1.List
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("list operation not supported for resource type %s", req.Msg.Resource.Type))
 	}
 
-	res, err := rd.list.fn(ctx, listRequestFromProto(req.Msg))
+	listReq := listRequestFromProto(req.Msg)
+
+	// Upgrade the properties of the resource stored with an older SchemaVersion before the handler sees them.
+	if err := rd.migrate(listReq.Resource); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("migrate resource properties: %w", err))
+	}
+
+	res, err := rd.list.fn(ctx, listReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("list resources: %w", err))
+		return nil, handlerError("list resources", err)
 	}
 
 	// Validate each resource before returning them.
 	for _, r := range res.Resources {
+		if err := a.normalizeResource(r); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize resource properties: %w", err))
+		}
+
 		if err := rd.list.schema.output.Validate(r.Properties); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("validate resource properties: %w", err))
+			return nil, invalidOutputError("validate resource properties", err, rd.list.schema.output, r.Properties)
 		}
 	}
 
 	resources := make([]*appv1.Resource, 0, len(res.Resources))
 	for _, r := range res.Resources {
+		rd.finalizeResource(r)
+
 		resource, err := r.toProto()
 		if err != nil {
 			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("convert resource to proto: %w", err))
@@ -291,18 +350,36 @@ func (a *App) ExecuteResourceAction(ctx context.Context, req *connect.Request[ap
 
 	actionReq := actionRequestFromProto(req.Msg)
 
-	action.InputSchema.injectDefaults(actionReq.Input)
+	if rd, ok := a.getResourceDefinition(req.Msg.Resource.Type); ok {
+		if err := rd.migrate(actionReq.Resource); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("migrate resource properties: %w", err))
+		}
+	}
+
+	a.prepareInput(action.InputSchema, actionReq.Input)
 	if err := action.InputSchema.Validate(actionReq.Input); err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("validate action input: %w", err))
+		return nil, invalidInputError("validate action input", err, action.InputSchema, actionReq.Input, actionReq.Environment)
+	}
+
+	if action.Validate != nil {
+		if err := action.Validate(ctx, actionReq); err != nil {
+			return nil, validationFuncError("validate action input", err, action.InputSchema, actionReq.Input, actionReq.Environment)
+		}
 	}
 
 	res, err := action.Handler(ctx, actionReq)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("execute action: %w", err))
+		return nil, handlerError("execute action", err)
 	}
 
+	output, err := normalizeProperties(res.Output, a.valueOptions)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("normalize action output: %w", err))
+	}
+	res.Output = output
+
 	if err := action.OutputSchema.Validate(res.Output); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("validate action output: %w", err))
+		return nil, invalidOutputError("validate action output", err, action.OutputSchema, res.Output)
 	}
 
 	o, err := structpb.NewStruct(res.Output)
@@ -358,6 +435,41 @@ func (a *App) HealthCheck(ctx context.Context, req *connect.Request[appv1.Health
 	}
 }
 
+// handlerError wraps an error returned by an OperationFunc, ListFunc or action Handler.
+// Errors that wrap ErrNotFound fail with CodeNotFound, every other error with CodeInternal.
+func handlerError(msg string, err error) *connect.Error {
+	code := connect.CodeInternal
+	if errors.Is(err, ErrNotFound) {
+		code = connect.CodeNotFound
+	}
+
+	return connect.NewError(code, fmt.Errorf("%s: %w", msg, err))
+}
+
+// normalizeResource converts the Go values in the resource properties to JSON values.
+func (a *App) normalizeResource(r *Resource) error {
+	if r == nil {
+		return nil
+	}
+
+	properties, err := normalizeProperties(r.Properties, a.valueOptions)
+	if err != nil {
+		return err
+	}
+	r.Properties = properties
+
+	return nil
+}
+
+// prepareInput coerces the input types, if enabled, and injects default values from the schema.
+func (a *App) prepareInput(s *JSONSchema, input map[string]any) {
+	if a.coerceTypes {
+		s.coerceTypes(input)
+	}
+
+	s.injectDefaults(input)
+}
+
 func operationForType(rd *ResourceDefinition, op appv1.ResourceOperation) *operation {
 	switch op {
 	case appv1.ResourceOperation_RESOURCE_OPERATION_CREATE:
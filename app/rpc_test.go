@@ -213,7 +213,7 @@ func TestExecuteResourceOperation_Create(t *testing.T) {
 				}),
 				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
 			},
-			err: fmt.Errorf("invalid_argument: validate create input: jsonschema validation failed"),
+			err: fmt.Errorf("invalid_argument: validate create input: /property2: missing property 'property2'"),
 		},
 		{
 			desc:         "ERR - Create Error",
@@ -245,7 +245,7 @@ func TestExecuteResourceOperation_Create(t *testing.T) {
 				}),
 				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
 			},
-			err: fmt.Errorf("internal: validate create output: jsonschema validation failed"),
+			err: fmt.Errorf("internal: validate create output: /property1: missing property 'property1'; /property2: missing property 'property2'"),
 		},
 	}
 	for _, tc := range testCases {
@@ -378,7 +378,7 @@ func TestExecuteResourceOperation_Update(t *testing.T) {
 				}),
 				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_UPDATE,
 			},
-			err: fmt.Errorf("invalid_argument: validate update input: jsonschema validation failed"),
+			err: fmt.Errorf("invalid_argument: validate update input: /property2: missing property 'property2'"),
 		},
 		{
 			desc:         "ERR - Update Error",
@@ -412,7 +412,7 @@ func TestExecuteResourceOperation_Update(t *testing.T) {
 				}),
 				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_UPDATE,
 			},
-			err: fmt.Errorf("internal: validate update output: jsonschema validation failed"),
+			err: fmt.Errorf("internal: validate update output: /property1: missing property 'property1'; /property2: missing property 'property2'"),
 		},
 	}
 	for _, tc := range testCases {
@@ -665,6 +665,19 @@ func TestExecuteResourceOperation_Read(t *testing.T) {
 			readErr: fmt.Errorf("read error"),
 			err:     fmt.Errorf("internal: read resource: read error"),
 		},
+		{
+			desc:       "ERR - Read Not Found",
+			enableRead: true,
+			req: &appv1.ExecuteResourceOperationRequest{
+				Resource: &appv1.Resource{
+					Type:       "example",
+					ExternalId: "example-1",
+				},
+				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
+			},
+			readErr: fmt.Errorf("get example-1: %w", ErrNotFound),
+			err:     fmt.Errorf("not_found: read resource: get example-1: resource not found"),
+		},
 		{
 			desc:             "ERR - Invalid Output",
 			enableRead:       true,
@@ -676,7 +689,7 @@ func TestExecuteResourceOperation_Read(t *testing.T) {
 				},
 				Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
 			},
-			err: fmt.Errorf("internal: validate read output: jsonschema validation failed"),
+			err: fmt.Errorf("internal: validate read output: /property1: missing property 'property1'; /property2: missing property 'property2'"),
 		},
 	}
 	for _, tc := range testCases {
@@ -742,6 +755,7 @@ func TestListResources(t *testing.T) {
 		want             *connect.Response[appv1.ListResourcesResponse]
 		req              *appv1.ListResourcesRequest
 		propertiesSchema *JSONSchema
+		linkTemplates    []LinkTemplate
 		enableList       bool
 		listErr          error
 		err              error
@@ -824,6 +838,53 @@ func TestListResources(t *testing.T) {
 				},
 			}),
 		},
+		{
+			desc:         "OK - Link Templates",
+			enableList:   true,
+			numResources: 1,
+			linkTemplates: []LinkTemplate{
+				{
+					URL:   "https://console.example.com/{{ resource.external_id }}?key={{ resource.key }}",
+					Title: "{{ resource.display_name }} Console",
+					Type:  LinkTypeExternal,
+				},
+				{
+					URL:   "https://console.example.com/{{ resource.missing }}",
+					Title: "Skipped",
+					Type:  LinkTypeExternal,
+				},
+			},
+			req: &appv1.ListResourcesRequest{
+				Resource: &appv1.Resource{
+					Type: "example",
+				},
+			},
+			want: connect.NewResponse(&appv1.ListResourcesResponse{
+				Resources: []*appv1.Resource{
+					{
+						Type:        "example",
+						ExternalId:  "example-1",
+						DisplayName: "Example",
+						Properties: mustNewStruct(map[string]any{
+							"key":       "value",
+							"other_key": "other_value",
+						}),
+						Links: []*appv1.Link{
+							{
+								Url:   "http://example.com",
+								Title: "Example",
+								Type:  appv1.LinkType_LINK_TYPE_DOCUMENTATION,
+							},
+							{
+								Url:   "https://console.example.com/example-1?key=value",
+								Title: "Example Console",
+								Type:  appv1.LinkType_LINK_TYPE_EXTERNAL,
+							},
+						},
+					},
+				},
+			}),
+		},
 		{
 			desc: "ERR - List Disabled",
 			req: &appv1.ListResourcesRequest{
@@ -846,6 +907,17 @@ func TestListResources(t *testing.T) {
 			listErr: fmt.Errorf("list error"),
 			err:     fmt.Errorf("internal: list resources: list error"),
 		},
+		{
+			desc:       "ERR - List Not Found",
+			enableList: true,
+			req: &appv1.ListResourcesRequest{
+				Resource: &appv1.Resource{
+					Type: "example",
+				},
+			},
+			listErr: fmt.Errorf("list parent: %w", ErrNotFound),
+			err:     fmt.Errorf("not_found: list resources: list parent: resource not found"),
+		},
 		{
 			desc:             "ERR - Invalid Output",
 			enableList:       true,
@@ -857,7 +929,7 @@ func TestListResources(t *testing.T) {
 				},
 				Next: "1",
 			},
-			err: fmt.Errorf("internal: validate resource properties: jsonschema validation failed"),
+			err: fmt.Errorf("internal: validate resource properties: /property1: missing property 'property1'; /property2: missing property 'property2'"),
 		},
 		{
 			desc: "ERR - Resource Missing",
@@ -876,6 +948,7 @@ func TestListResources(t *testing.T) {
 			if tc.propertiesSchema != nil {
 				rd.PropertiesSchema = tc.propertiesSchema
 			}
+			rd.LinkTemplates = tc.linkTemplates
 
 			if tc.enableList {
 				rd.ListFn(func(_ context.Context, req *ListRequest) (*ListResponse, error) {
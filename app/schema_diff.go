@@ -0,0 +1,412 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=SchemaChangeKind -linecomment
+
+// SchemaChangeKind classifies a change between two versions of a schema.
+type SchemaChangeKind int
+
+const (
+	SchemaChangePropertyAdded                  SchemaChangeKind = iota + 1 // property added
+	SchemaChangePropertyRemoved                                            // property removed
+	SchemaChangeRequiredAdded                                              // required added
+	SchemaChangeRequiredRemoved                                            // required removed
+	SchemaChangeTypeNarrowed                                               // type narrowed
+	SchemaChangeTypeWidened                                                // type widened
+	SchemaChangeEnumNarrowed                                               // enum narrowed
+	SchemaChangeEnumWidened                                                // enum widened
+	SchemaChangeConstraintTightened                                        // constraint tightened
+	SchemaChangeConstraintRelaxed                                          // constraint relaxed
+	SchemaChangeAdditionalPropertiesDisallowed                             // additional properties disallowed
+	SchemaChangeAdditionalPropertiesAllowed                                // additional properties allowed
+)
+
+// SchemaChange describes a single difference between two versions of a schema.
+type SchemaChange struct {
+	// Path is the JSON pointer to the changed schema, for example "/properties/region".
+	Path string
+	// Kind classifies the change.
+	Kind SchemaChangeKind
+	// Breaking is true when data that was valid against the old schema may no longer be valid against the new one.
+	Breaking bool
+	// Message describes the change.
+	Message string
+}
+
+func (c SchemaChange) String() string {
+	compatibility := "compatible"
+	if c.Breaking {
+		compatibility = "breaking"
+	}
+
+	return fmt.Sprintf("%s: %s (%s): %s", c.Path, c.Kind, compatibility, c.Message)
+}
+
+// SchemaChanges is the list of differences between two versions of a schema.
+type SchemaChanges []SchemaChange
+
+// Breaking returns the changes that are not backward-compatible.
+func (c SchemaChanges) Breaking() SchemaChanges {
+	var breaking SchemaChanges
+	for _, change := range c {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+
+	return breaking
+}
+
+// Err returns an error listing the breaking changes, or nil if every change is backward-compatible.
+// It can be used in tests to gate releases:
+//
+//	require.NoError(t, app.DiffSchemas(previous, current).Err())
+func (c SchemaChanges) Err() error {
+	breaking := c.Breaking()
+	if len(breaking) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(breaking))
+	for _, change := range breaking {
+		lines = append(lines, change.String())
+	}
+
+	return fmt.Errorf("breaking schema changes:\n%s", strings.Join(lines, "\n"))
+}
+
+// DiffSchemas compares two versions of a schema, such as a PropertiesSchema or a create input schema,
+// and classifies every change as backward-compatible or breaking. A change is breaking when data that
+// is valid against oldSchema, such as existing resources stored by Tempest, may no longer be valid
+// against newSchema.
+//
+// The type, enum, const, format, length, item count and numeric bounds, pattern, properties, required
+// and additionalProperties keywords are compared, recursively in properties, items, $ref and allOf.
+// A $ref, items or allOf subschema that is added or removed is compared against an empty schema, so
+// inlining or extracting a subschema is reported as a change. The anyOf, oneOf, not and if/then/else keywords
+// are not compared.
+func DiffSchemas(oldSchema, newSchema *JSONSchema) SchemaChanges {
+	d := schemaDiffer{visited: make(map[[2]*jsonschema.Schema]bool)}
+	d.diff("", schemaOf(oldSchema), schemaOf(newSchema))
+
+	return d.changes
+}
+
+func schemaOf(j *JSONSchema) *jsonschema.Schema {
+	if j == nil || j.Schema == nil {
+		return &jsonschema.Schema{}
+	}
+
+	return j.Schema
+}
+
+type schemaDiffer struct {
+	changes SchemaChanges
+	// visited stops the comparison of recursive schemas.
+	visited map[[2]*jsonschema.Schema]bool
+}
+
+func (d *schemaDiffer) add(path string, kind SchemaChangeKind, breaking bool, format string, args ...any) {
+	d.changes = append(d.changes, SchemaChange{
+		Path:     path,
+		Kind:     kind,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// diff compares the schemas at the given path.
+func (d *schemaDiffer) diff(path string, o, n *jsonschema.Schema) {
+	if o == nil {
+		o = &jsonschema.Schema{}
+	}
+	if n == nil {
+		n = &jsonschema.Schema{}
+	}
+	if d.visited[[2]*jsonschema.Schema{o, n}] {
+		return
+	}
+	d.visited[[2]*jsonschema.Schema{o, n}] = true
+
+	d.diffTypes(path, o.Types, n.Types)
+	d.diffEnum(path, o.Enum, n.Enum)
+	d.diffConst(path, o.Const, n.Const)
+	d.diffFormat(path, o.Format, n.Format)
+	d.diffConstraints(path, o, n)
+	d.diffObject(path, o, n)
+
+	if o.Ref != nil || n.Ref != nil {
+		d.diff(path+"/$ref", o.Ref, n.Ref)
+	}
+	for i := range max(len(o.AllOf), len(n.AllOf)) {
+		d.diff(fmt.Sprintf("%s/allOf/%d", path, i), schemaAt(o.AllOf, i), schemaAt(n.AllOf, i))
+	}
+
+	// Compare the items of arrays. Items added or removed are compared with an empty schema.
+	if oi, ni := itemsSchema(o), itemsSchema(n); oi != nil || ni != nil {
+		d.diff(path+"/items", oi, ni)
+	}
+}
+
+// itemsSchema returns the schema of the items of an array, for every draft, or nil if it has none.
+// Tuple items, an array of schemas before draft 2020-12, are not compared.
+func itemsSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	if s.Items2020 != nil {
+		return s.Items2020
+	}
+	if items, ok := s.Items.(*jsonschema.Schema); ok {
+		return items
+	}
+
+	return nil
+}
+
+func (d *schemaDiffer) diffObject(path string, o, n *jsonschema.Schema) {
+	for _, name := range slices.Sorted(maps.Keys(n.Properties)) {
+		if _, ok := o.Properties[name]; !ok {
+			d.add(path+"/properties/"+name, SchemaChangePropertyAdded, false, "property %q was added", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(o.Properties)) {
+		np, ok := n.Properties[name]
+		if !ok {
+			d.add(path+"/properties/"+name, SchemaChangePropertyRemoved, true, "property %q was removed", name)
+			continue
+		}
+
+		d.diff(path+"/properties/"+name, o.Properties[name], np)
+	}
+
+	for _, name := range n.Required {
+		if !slices.Contains(o.Required, name) {
+			d.add(path+"/required", SchemaChangeRequiredAdded, true, "property %q is now required", name)
+		}
+	}
+
+	for _, name := range o.Required {
+		if !slices.Contains(n.Required, name) {
+			d.add(path+"/required", SchemaChangeRequiredRemoved, false, "property %q is no longer required", name)
+		}
+	}
+
+	oldAllowed, newAllowed := additionalPropertiesAllowed(o), additionalPropertiesAllowed(n)
+	switch {
+	case oldAllowed && !newAllowed:
+		d.add(path+"/additionalProperties", SchemaChangeAdditionalPropertiesDisallowed, true, "additional properties are no longer allowed")
+	case !oldAllowed && newAllowed:
+		d.add(path+"/additionalProperties", SchemaChangeAdditionalPropertiesAllowed, false, "additional properties are now allowed")
+	}
+}
+
+func (d *schemaDiffer) diffTypes(path string, o, n *jsonschema.Types) {
+	if diffTypesAccept(n, o) {
+		if !diffTypesAccept(o, n) {
+			d.add(path+"/type", SchemaChangeTypeWidened, false, "type changed from %s to %s", typesString(o), typesString(n))
+		}
+		return
+	}
+
+	d.add(path+"/type", SchemaChangeTypeNarrowed, true, "type changed from %s to %s", typesString(o), typesString(n))
+}
+
+// diffTypesAccept reports whether every value of the value types is accepted by the accepting types.
+// Unlike typesAccept, a missing type list accepts every type, so it is only accepted by another missing list.
+func diffTypesAccept(accepting, value *jsonschema.Types) bool {
+	if accepting == nil || accepting.IsEmpty() {
+		return true
+	}
+	if value == nil || value.IsEmpty() {
+		return false
+	}
+
+	return typesAccept(accepting, value)
+}
+
+func (d *schemaDiffer) diffConst(path string, o, n *any) {
+	switch {
+	case o == nil && n == nil:
+		return
+	case o == nil:
+		d.add(path+"/const", SchemaChangeEnumNarrowed, true, "const %s was added", enumString([]any{*n}))
+	case n == nil:
+		d.add(path+"/const", SchemaChangeEnumWidened, false, "const %s was removed", enumString([]any{*o}))
+	default:
+		oldValue, newValue := enumKeys([]any{*o})[0], enumKeys([]any{*n})[0]
+		if oldValue != newValue {
+			d.add(path+"/const", SchemaChangeEnumNarrowed, true, "const changed from %s to %s", oldValue, newValue)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffFormat(path string, o, n *jsonschema.Format) {
+	oldFormat, newFormat := formatName(o), formatName(n)
+	switch {
+	case oldFormat == newFormat:
+	case newFormat == "":
+		d.add(path+"/format", SchemaChangeConstraintRelaxed, false, "format %q was removed", oldFormat)
+	case oldFormat == "":
+		d.add(path+"/format", SchemaChangeConstraintTightened, true, "format %q was added", newFormat)
+	default:
+		d.add(path+"/format", SchemaChangeConstraintTightened, true, "format changed from %q to %q", oldFormat, newFormat)
+	}
+}
+
+func (d *schemaDiffer) diffEnum(path string, o, n *jsonschema.Enum) {
+	switch {
+	case o == nil && n == nil:
+		return
+	case o == nil:
+		d.add(path+"/enum", SchemaChangeEnumNarrowed, true, "enum %s was added", enumString(n.Values))
+		return
+	case n == nil:
+		d.add(path+"/enum", SchemaChangeEnumWidened, false, "enum %s was removed", enumString(o.Values))
+		return
+	}
+
+	oldValues, newValues := enumKeys(o.Values), enumKeys(n.Values)
+
+	var removed, added []string
+	for _, v := range oldValues {
+		if !slices.Contains(newValues, v) {
+			removed = append(removed, v)
+		}
+	}
+	for _, v := range newValues {
+		if !slices.Contains(oldValues, v) {
+			added = append(added, v)
+		}
+	}
+
+	if len(removed) > 0 {
+		d.add(path+"/enum", SchemaChangeEnumNarrowed, true, "enum values %s were removed", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(path+"/enum", SchemaChangeEnumWidened, false, "enum values %s were added", strings.Join(added, ", "))
+	}
+}
+
+func (d *schemaDiffer) diffConstraints(path string, o, n *jsonschema.Schema) {
+	d.diffLowerBound(path+"/minLength", intRat(o.MinLength), intRat(n.MinLength))
+	d.diffUpperBound(path+"/maxLength", intRat(o.MaxLength), intRat(n.MaxLength))
+	d.diffLowerBound(path+"/minItems", intRat(o.MinItems), intRat(n.MinItems))
+	d.diffUpperBound(path+"/maxItems", intRat(o.MaxItems), intRat(n.MaxItems))
+	d.diffLowerBound(path+"/minimum", o.Minimum, n.Minimum)
+	d.diffUpperBound(path+"/maximum", o.Maximum, n.Maximum)
+	d.diffLowerBound(path+"/exclusiveMinimum", o.ExclusiveMinimum, n.ExclusiveMinimum)
+	d.diffUpperBound(path+"/exclusiveMaximum", o.ExclusiveMaximum, n.ExclusiveMaximum)
+
+	oldPattern, newPattern := regexpString(o.Pattern), regexpString(n.Pattern)
+	switch {
+	case oldPattern == newPattern:
+	case newPattern == "":
+		d.add(path+"/pattern", SchemaChangeConstraintRelaxed, false, "pattern %q was removed", oldPattern)
+	case oldPattern == "":
+		d.add(path+"/pattern", SchemaChangeConstraintTightened, true, "pattern %q was added", newPattern)
+	default:
+		// Whether one pattern is a superset of another can't be determined, so any other change is breaking.
+		d.add(path+"/pattern", SchemaChangeConstraintTightened, true, "pattern changed from %q to %q", oldPattern, newPattern)
+	}
+}
+
+// diffLowerBound compares a minimum, where a nil bound is unbounded.
+func (d *schemaDiffer) diffLowerBound(path string, o, n *big.Rat) {
+	switch {
+	case n != nil && (o == nil || n.Cmp(o) > 0):
+		d.add(path, SchemaChangeConstraintTightened, true, "lower bound raised from %s to %s", ratString(o), ratString(n))
+	case o != nil && (n == nil || n.Cmp(o) < 0):
+		d.add(path, SchemaChangeConstraintRelaxed, false, "lower bound lowered from %s to %s", ratString(o), ratString(n))
+	}
+}
+
+// diffUpperBound compares a maximum, where a nil bound is unbounded.
+func (d *schemaDiffer) diffUpperBound(path string, o, n *big.Rat) {
+	switch {
+	case n != nil && (o == nil || n.Cmp(o) < 0):
+		d.add(path, SchemaChangeConstraintTightened, true, "upper bound lowered from %s to %s", ratString(o), ratString(n))
+	case o != nil && (n == nil || n.Cmp(o) > 0):
+		d.add(path, SchemaChangeConstraintRelaxed, false, "upper bound raised from %s to %s", ratString(o), ratString(n))
+	}
+}
+
+// additionalPropertiesAllowed reports whether the schema allows properties that are not listed in its properties.
+func additionalPropertiesAllowed(s *jsonschema.Schema) bool {
+	allowed, ok := s.AdditionalProperties.(bool)
+	return !ok || allowed
+}
+
+func schemaAt(schemas []*jsonschema.Schema, i int) *jsonschema.Schema {
+	if i >= len(schemas) {
+		return nil
+	}
+
+	return schemas[i]
+}
+
+func formatName(f *jsonschema.Format) string {
+	if f == nil {
+		return ""
+	}
+
+	return f.Name
+}
+
+func typesString(t *jsonschema.Types) string {
+	if t == nil || t.IsEmpty() {
+		return "any"
+	}
+
+	return t.String()
+}
+
+func enumKeys(values []any) []string {
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			keys = append(keys, fmt.Sprint(v))
+			continue
+		}
+		keys = append(keys, string(b))
+	}
+
+	return keys
+}
+
+func enumString(values []any) string {
+	return "[" + strings.Join(enumKeys(values), ", ") + "]"
+}
+
+func intRat(i *int) *big.Rat {
+	if i == nil {
+		return nil
+	}
+
+	return big.NewRat(int64(*i), 1)
+}
+
+func ratString(r *big.Rat) string {
+	if r == nil {
+		return "none"
+	}
+
+	return r.RatString()
+}
+
+func regexpString(r jsonschema.Regexp) string {
+	if r == nil {
+		return ""
+	}
+
+	return r.String()
+}
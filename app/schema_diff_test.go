@@ -0,0 +1,190 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var baseDiffSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"name": {"type": "string", "maxLength": 63},
+		"engine": {"type": "string", "enum": ["postgres", "mysql"]},
+		"replicas": {"type": "integer", "minimum": 1},
+		"legacy": {"type": "string"}
+	},
+	"required": ["name"]
+}`)
+
+func TestDiffSchemas(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		newSchema []byte
+		changes   SchemaChanges
+	}{
+		{
+			desc:      "OK - No Changes",
+			newSchema: baseDiffSchema,
+		},
+		{
+			desc: "OK - Compatible Changes",
+			newSchema: []byte(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"properties": {
+					"name": {"type": "string", "maxLength": 128},
+					"engine": {"type": "string", "enum": ["postgres", "mysql", "mariadb"]},
+					"replicas": {"type": "number"},
+					"legacy": {"type": "string"},
+					"tier": {"type": "string"}
+				}
+			}`),
+			changes: SchemaChanges{
+				{Path: "/properties/name/maxLength", Kind: SchemaChangeConstraintRelaxed, Message: "upper bound raised from 63 to 128"},
+				{Path: "/properties/engine/enum", Kind: SchemaChangeEnumWidened, Message: `enum values "mariadb" were added`},
+				{Path: "/properties/replicas/type", Kind: SchemaChangeTypeWidened, Message: "type changed from [integer] to [number]"},
+				{Path: "/properties/replicas/minimum", Kind: SchemaChangeConstraintRelaxed, Message: "lower bound lowered from 1 to none"},
+				{Path: "/properties/tier", Kind: SchemaChangePropertyAdded, Message: `property "tier" was added`},
+				{Path: "/required", Kind: SchemaChangeRequiredRemoved, Message: `property "name" is no longer required`},
+			},
+		},
+		{
+			desc: "ERR - Breaking Changes",
+			newSchema: []byte(`{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"properties": {
+					"name": {"type": "string", "maxLength": 63, "pattern": "^[a-z]+$"},
+					"engine": {"type": "string", "enum": ["postgres"]},
+					"replicas": {"type": "string"},
+					"region": {"type": "string"}
+				},
+				"required": ["name", "region"],
+				"additionalProperties": false
+			}`),
+			changes: SchemaChanges{
+				{Path: "/properties/engine/enum", Kind: SchemaChangeEnumNarrowed, Breaking: true, Message: `enum values "mysql" were removed`},
+				{Path: "/properties/legacy", Kind: SchemaChangePropertyRemoved, Breaking: true, Message: `property "legacy" was removed`},
+				{Path: "/properties/name/pattern", Kind: SchemaChangeConstraintTightened, Breaking: true, Message: `pattern "^[a-z]+$" was added`},
+				{Path: "/properties/region", Kind: SchemaChangePropertyAdded, Message: `property "region" was added`},
+				{Path: "/properties/replicas/type", Kind: SchemaChangeTypeNarrowed, Breaking: true, Message: "type changed from [integer] to [string]"},
+				{Path: "/properties/replicas/minimum", Kind: SchemaChangeConstraintRelaxed, Message: "lower bound lowered from 1 to none"},
+				{Path: "/required", Kind: SchemaChangeRequiredAdded, Breaking: true, Message: `property "region" is now required`},
+				{Path: "/additionalProperties", Kind: SchemaChangeAdditionalPropertiesDisallowed, Breaking: true, Message: "additional properties are no longer allowed"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			changes := DiffSchemas(MustParseJSONSchema(baseDiffSchema), MustParseJSONSchema(tc.newSchema))
+			assert.ElementsMatch(t, tc.changes, changes)
+
+			if len(tc.changes.Breaking()) == 0 {
+				require.NoError(t, changes.Err())
+				return
+			}
+			assert.ErrorContains(t, changes.Err(), "breaking schema changes")
+		})
+	}
+}
+
+func TestDiffSchemasKeywords(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		oldSchema string
+		newSchema string
+		changes   SchemaChanges
+	}{
+		{
+			desc:      "ERR - Type Added",
+			oldSchema: `{"properties": {"a": {}}}`,
+			newSchema: `{"properties": {"a": {"type": "string"}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/a/type", Kind: SchemaChangeTypeNarrowed, Breaking: true, Message: "type changed from any to [string]"},
+			},
+		},
+		{
+			desc:      "OK - Type Removed",
+			oldSchema: `{"properties": {"a": {"type": "string"}}}`,
+			newSchema: `{"properties": {"a": {}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/a/type", Kind: SchemaChangeTypeWidened, Message: "type changed from [string] to any"},
+			},
+		},
+		{
+			desc:      "ERR - Const",
+			oldSchema: `{"properties": {"a": {"const": "x"}, "b": {}, "c": {"const": 1}}}`,
+			newSchema: `{"properties": {"a": {"const": "y"}, "b": {"const": true}, "c": {}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/a/const", Kind: SchemaChangeEnumNarrowed, Breaking: true, Message: `const changed from "x" to "y"`},
+				{Path: "/properties/b/const", Kind: SchemaChangeEnumNarrowed, Breaking: true, Message: "const [true] was added"},
+				{Path: "/properties/c/const", Kind: SchemaChangeEnumWidened, Message: "const [1] was removed"},
+			},
+		},
+		{
+			desc:      "ERR - Format",
+			oldSchema: `{"$schema": "http://json-schema.org/draft-07/schema#", "properties": {"a": {"type": "string", "format": "email"}, "b": {"type": "string"}, "c": {"type": "string", "format": "uri"}}}`,
+			newSchema: `{"$schema": "http://json-schema.org/draft-07/schema#", "properties": {"a": {"type": "string", "format": "hostname"}, "b": {"type": "string", "format": "cidr"}, "c": {"type": "string"}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/a/format", Kind: SchemaChangeConstraintTightened, Breaking: true, Message: `format changed from "email" to "hostname"`},
+				{Path: "/properties/b/format", Kind: SchemaChangeConstraintTightened, Breaking: true, Message: `format "cidr" was added`},
+				{Path: "/properties/c/format", Kind: SchemaChangeConstraintRelaxed, Message: `format "uri" was removed`},
+			},
+		},
+		{
+			desc:      "ERR - Ref",
+			oldSchema: `{"definitions": {"tag": {"type": "string", "maxLength": 10}}, "properties": {"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}}}`,
+			newSchema: `{"definitions": {"tag": {"type": "string", "maxLength": 5}}, "properties": {"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/tags/items/$ref/maxLength", Kind: SchemaChangeConstraintTightened, Breaking: true, Message: "upper bound lowered from 10 to 5"},
+			},
+		},
+		{
+			desc:      "ERR - Items Added",
+			oldSchema: `{"properties": {"tags": {"type": "array"}}}`,
+			newSchema: `{"properties": {"tags": {"type": "array", "items": {"type": "string"}}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/tags/items/type", Kind: SchemaChangeTypeNarrowed, Breaking: true, Message: "type changed from any to [string]"},
+			},
+		},
+		{
+			desc:      "OK - Items Removed",
+			oldSchema: `{"properties": {"tags": {"type": "array", "items": {"type": "string"}}}}`,
+			newSchema: `{"properties": {"tags": {"type": "array"}}}`,
+			changes: SchemaChanges{
+				{Path: "/properties/tags/items/type", Kind: SchemaChangeTypeWidened, Message: "type changed from [string] to any"},
+			},
+		},
+		{
+			desc:      "ERR - AllOf",
+			oldSchema: `{"allOf": [{"required": ["a"]}]}`,
+			newSchema: `{"allOf": [{"required": ["a"]}, {"required": ["b"]}]}`,
+			changes: SchemaChanges{
+				{Path: "/allOf/1/required", Kind: SchemaChangeRequiredAdded, Breaking: true, Message: `property "b" is now required`},
+			},
+		},
+		{
+			desc:      "OK - Recursive Ref",
+			oldSchema: `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`,
+			newSchema: `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			changes := DiffSchemas(MustParseJSONSchema([]byte(tc.oldSchema)), MustParseJSONSchema([]byte(tc.newSchema)))
+			assert.ElementsMatch(t, tc.changes, changes)
+		})
+	}
+}
+
+func TestSchemaChangeString(t *testing.T) {
+	c := SchemaChange{
+		Path:     "/properties/legacy",
+		Kind:     SchemaChangePropertyRemoved,
+		Breaking: true,
+		Message:  `property "legacy" was removed`,
+	}
+
+	assert.Equal(t, `/properties/legacy: property removed (breaking): property "legacy" was removed`, c.String())
+	assert.Equal(t, "SchemaChangeKind(0)", SchemaChangeKind(0).String())
+}
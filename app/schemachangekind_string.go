@@ -0,0 +1,35 @@
+// Code generated by "stringer -type=SchemaChangeKind -linecomment"; DO NOT EDIT.
+
+package app
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[SchemaChangePropertyAdded-1]
+	_ = x[SchemaChangePropertyRemoved-2]
+	_ = x[SchemaChangeRequiredAdded-3]
+	_ = x[SchemaChangeRequiredRemoved-4]
+	_ = x[SchemaChangeTypeNarrowed-5]
+	_ = x[SchemaChangeTypeWidened-6]
+	_ = x[SchemaChangeEnumNarrowed-7]
+	_ = x[SchemaChangeEnumWidened-8]
+	_ = x[SchemaChangeConstraintTightened-9]
+	_ = x[SchemaChangeConstraintRelaxed-10]
+	_ = x[SchemaChangeAdditionalPropertiesDisallowed-11]
+	_ = x[SchemaChangeAdditionalPropertiesAllowed-12]
+}
+
+const _SchemaChangeKind_name = "property addedproperty removedrequired addedrequired removedtype narrowedtype widenedenum narrowedenum widenedconstraint tightenedconstraint relaxedadditional properties disallowedadditional properties allowed"
+
+var _SchemaChangeKind_index = [...]uint8{0, 14, 30, 44, 60, 73, 85, 98, 110, 130, 148, 180, 209}
+
+func (i SchemaChangeKind) String() string {
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_SchemaChangeKind_index)-1 {
+		return "SchemaChangeKind(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _SchemaChangeKind_name[_SchemaChangeKind_index[idx]:_SchemaChangeKind_index[idx+1]]
+}
@@ -0,0 +1,251 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const ActionNamePattern = `^[A-Za-z_][A-Za-z0-9_]*$`
+
+var actionNameRegex = regexp.MustCompile(ActionNamePattern)
+
+// Diagnostic describes a single consistency problem found in an App's definitions.
+type Diagnostic struct {
+	// Resource is the type of the ResourceDefinition the problem was found in.
+	Resource string
+	// Field is the path to the offending field within the ResourceDefinition,
+	// for example "Links[0].URL" or "Actions[1].Name".
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Field == "" {
+		return fmt.Sprintf("%s: %s", d.Resource, d.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", d.Resource, d.Field, d.Message)
+}
+
+// Diagnostics is the error returned by App.Validate. It contains every problem that was found.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, 0, len(d))
+	for _, diag := range d {
+		lines = append(lines, diag.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks that the App's ResourceDefinitions are coherent as a whole.
+// It returns Diagnostics listing every problem found, or nil if the App is consistent.
+func (a *App) Validate() error {
+	var diags Diagnostics
+
+	seen := make(map[string]bool, len(a.resourceDefinitions))
+	for _, rd := range a.resourceDefinitions {
+		if seen[rd.Type] {
+			diags = append(diags, Diagnostic{Resource: rd.Type, Field: "Type", Message: "duplicate resource type"})
+		}
+		seen[rd.Type] = true
+
+		diags = append(diags, rd.validate()...)
+	}
+
+	if len(diags) == 0 {
+		return nil
+	}
+
+	return diags
+}
+
+// validate returns the Diagnostics for a single ResourceDefinition.
+func (rd *ResourceDefinition) validate() Diagnostics {
+	var diags Diagnostics
+	add := func(field, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			Resource: rd.Type,
+			Field:    field,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if !resourceTypeRegex.MatchString(rd.Type) {
+		add("Type", "does not match pattern %s", ResourceTypePattern)
+	}
+
+	if strings.TrimSpace(rd.DisplayName) == "" {
+		add("DisplayName", "must not be empty")
+	}
+
+	if rd.LifecycleStage < LifecycleStageCode || rd.LifecycleStage > LifecycleStageOther {
+		add("LifecycleStage", "invalid lifecycle stage %d", rd.LifecycleStage)
+	}
+
+	for i, l := range rd.Links {
+		if err := validateLinkURL(l.URL); err != nil {
+			add(fmt.Sprintf("Links[%d].URL", i), "%s", err)
+		}
+	}
+
+	for i, lt := range rd.LinkTemplates {
+		field := fmt.Sprintf("LinkTemplates[%d]", i)
+
+		// Substitute a placeholder for every variable to check the shape of the URL. A variable at
+		// the start of the URL holds a whole URL, see LinkTemplate.
+		u, _ := renderTemplate(lt.URL, placeholderProperties(lt.URL), func(prefix, _, v string) string {
+			if prefix == "" {
+				return "https://placeholder.example.com"
+			}
+			return v
+		})
+		if err := validateLinkURL(u); err != nil {
+			add(field+".URL", "%s", err)
+		}
+
+		if rd.PropertiesSchema == nil {
+			continue
+		}
+
+		for _, v := range append(instructionVariables(lt.URL), instructionVariables(lt.Title)...) {
+			if _, ok := rd.PropertiesSchema.Properties[v]; !ok && v != "external_id" && v != "display_name" {
+				add(field, "variable resource.%s is not defined in the properties schema", v)
+			}
+		}
+	}
+
+	if rd.PropertiesSchema == nil {
+		add("PropertiesSchema", "must be set")
+	}
+
+	if rd.create != nil && rd.PropertiesSchema != nil {
+		for _, name := range slices.Sorted(maps.Keys(rd.create.schema.input.Properties)) {
+			input := rd.create.schema.input.Properties[name]
+			property, ok := rd.PropertiesSchema.Properties[name]
+			if !ok {
+				continue
+			}
+
+			if !typesAccept(property.Types, input.Types) {
+				add("create.InputSchema.properties."+name, "type %s is not compatible with properties schema type %s", input.Types, property.Types)
+			}
+		}
+	}
+
+	for _, v := range instructionVariables(rd.InstructionsMarkdown) {
+		if rd.PropertiesSchema == nil {
+			break
+		}
+
+		if _, ok := rd.PropertiesSchema.Properties[v]; !ok {
+			add("InstructionsMarkdown", "variable resource.%s is not defined in the properties schema", v)
+		}
+	}
+
+	names := make(map[string]bool, len(rd.actions))
+	for i, ad := range rd.actions {
+		field := fmt.Sprintf("Actions[%d]", i)
+
+		if !actionNameRegex.MatchString(ad.Name) {
+			add(field+".Name", "%q does not match pattern %s", ad.Name, ActionNamePattern)
+		}
+
+		if names[ad.Name] {
+			add(field+".Name", "duplicate action name %q", ad.Name)
+		}
+		names[ad.Name] = true
+
+		if ad.Handler == nil {
+			add(field+".Handler", "must be set")
+		}
+	}
+
+	for _, property := range slices.Sorted(maps.Keys(rd.options)) {
+		var found bool
+		for _, op := range []*operation{rd.create, rd.update} {
+			if op != nil && op.schema.input != nil && op.schema.input.Properties[property] != nil {
+				found = true
+			}
+		}
+
+		if !found {
+			add("Options", "property %q is not defined in the create or update input schema", property)
+		}
+	}
+
+	if rd.SchemaVersion < 0 {
+		add("SchemaVersion", "must not be negative, got %d", rd.SchemaVersion)
+	}
+
+	if rd.PropertiesSchema != nil && rd.PropertiesSchema.Properties[SchemaVersionProperty] != nil {
+		add("PropertiesSchema", "property %q is reserved for the SchemaVersion", SchemaVersionProperty)
+	}
+
+	for _, m := range rd.migrations {
+		if m.from >= rd.SchemaVersion {
+			add("SchemaVersion", "migration from version %d is never run, the current version is %d", m.from, rd.SchemaVersion)
+		}
+	}
+
+	return diags
+}
+
+// validateLinkURL checks that the URL is absolute and uses the http or https scheme.
+func validateLinkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL %q: scheme must be http or https", raw)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: host is required", raw)
+	}
+
+	return nil
+}
+
+// placeholderProperties returns a placeholder value for every variable in the template.
+func placeholderProperties(tmpl string) map[string]any {
+	properties := make(map[string]any)
+	for _, v := range instructionVariables(tmpl) {
+		properties[v] = "placeholder"
+	}
+
+	return properties
+}
+
+// typesAccept reports whether every type in value is accepted by accepting.
+// Missing type lists are always compatible. Integers are accepted where numbers are.
+func typesAccept(accepting, value *jsonschema.Types) bool {
+	if accepting == nil || accepting.IsEmpty() || value == nil || value.IsEmpty() {
+		return true
+	}
+
+	allowed := accepting.ToStrings()
+	for _, t := range value.ToStrings() {
+		if slices.Contains(allowed, t) {
+			continue
+		}
+
+		if t == "integer" && slices.Contains(allowed, "number") {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
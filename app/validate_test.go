@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var typedPropertiesSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"name": {
+			"type": "string"
+		},
+		"size": {
+			"type": "number"
+		}
+	}
+}`)
+
+func TestAppValidate(t *testing.T) {
+	simpleActionFn := func(_ context.Context, _ *ActionRequest) (*ActionResponse, error) {
+		return &ActionResponse{}, nil
+	}
+	properties := MustParseJSONSchema(typedPropertiesSchema)
+
+	testCases := []struct {
+		desc  string
+		rd    func() ResourceDefinition
+		diags Diagnostics
+	}{
+		{
+			desc: "OK",
+			rd: func() ResourceDefinition {
+				rd := generateRD([]string{"create", "read"})
+				rd.actions[0].Handler = simpleActionFn
+				return rd
+			},
+		},
+		{
+			desc: "ERR - Definition fields",
+			rd: func() ResourceDefinition {
+				rd := generateRD(nil)
+				rd.actions[0].Handler = simpleActionFn
+				rd.DisplayName = ""
+				rd.LifecycleStage = 0
+				rd.Links = append(rd.Links, Link{URL: "example.com/docs"}, Link{URL: "ftp://example.com"})
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "DisplayName", Message: "must not be empty"},
+				{Resource: "example", Field: "LifecycleStage", Message: "invalid lifecycle stage 0"},
+				{Resource: "example", Field: "Links[1].URL", Message: `invalid URL "example.com/docs": scheme must be http or https`},
+				{Resource: "example", Field: "Links[2].URL", Message: `invalid URL "ftp://example.com": scheme must be http or https`},
+			},
+		},
+		{
+			desc: "ERR - Schema consistency",
+			rd: func() ResourceDefinition {
+				rd := generateRD(nil)
+				rd.actions[0].Handler = simpleActionFn
+				rd.PropertiesSchema = properties
+				rd.InstructionsMarkdown = "Open {{ resource.name }} or {{resource.missing}}."
+				rd.LinkTemplates = []LinkTemplate{
+					{URL: "https://example.com/{{ resource.name }}/{{ resource.external_id }}"},
+					{URL: "/{{ resource.nope }}"},
+					{URL: "{{ resource.name }}/console"},
+				}
+				rd.CreateFn(simpleOpFn, MustParseJSONSchema([]byte(`{
+					"properties": {
+						"name": {"type": "integer"},
+						"size": {"type": "integer"},
+						"extra": {"type": "boolean"}
+					}
+				}`)))
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "LinkTemplates[1].URL", Message: `invalid URL "/placeholder": scheme must be http or https`},
+				{Resource: "example", Field: "LinkTemplates[1]", Message: "variable resource.nope is not defined in the properties schema"},
+				{Resource: "example", Field: "create.InputSchema.properties.name", Message: "type [integer] is not compatible with properties schema type [string]"},
+				{Resource: "example", Field: "InstructionsMarkdown", Message: "variable resource.missing is not defined in the properties schema"},
+			},
+		},
+		{
+			desc: "ERR - Actions",
+			rd: func() ResourceDefinition {
+				rd := generateRD(nil)
+				rd.actions = []ActionDefinition{
+					{Name: "do_something", Handler: simpleActionFn},
+					{Name: "do_something", Handler: simpleActionFn},
+					{Name: "do-something"},
+				}
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "Actions[1].Name", Message: `duplicate action name "do_something"`},
+				{Resource: "example", Field: "Actions[2].Name", Message: `"do-something" does not match pattern ^[A-Za-z_][A-Za-z0-9_]*$`},
+				{Resource: "example", Field: "Actions[2].Handler", Message: "must be set"},
+			},
+		},
+		{
+			desc: "ERR - Options",
+			rd: func() ResourceDefinition {
+				rd := generateRD([]string{"create"})
+				rd.actions[0].Handler = simpleActionFn
+				rd.AddOptionsFunc("missing", subnetOptions)
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "Options", Message: `property "missing" is not defined in the create or update input schema`},
+			},
+		},
+		{
+			desc: "ERR - Migrations",
+			rd: func() ResourceDefinition {
+				rd := generateRD(nil)
+				rd.actions[0].Handler = simpleActionFn
+				rd.SchemaVersion = 1
+				rd.AddMigration(0, renameProperty("name", "title"))
+				rd.AddMigration(1, renameProperty("title", "label"))
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "SchemaVersion", Message: "migration from version 1 is never run, the current version is 1"},
+			},
+		},
+		{
+			desc: "ERR - Reserved schema version property",
+			rd: func() ResourceDefinition {
+				rd := generateRD(nil)
+				rd.actions[0].Handler = simpleActionFn
+				rd.PropertiesSchema = MustParseJSONSchema([]byte(`{
+					"$schema": "http://json-schema.org/draft-07/schema#",
+					"properties": {
+						"_schema_version": {"type": "number"}
+					}
+				}`))
+				return rd
+			},
+			diags: Diagnostics{
+				{Resource: "example", Field: "PropertiesSchema", Message: `property "_schema_version" is reserved for the SchemaVersion`},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			app := &App{
+				resourceDefinitions: []ResourceDefinition{tc.rd()},
+			}
+
+			err := app.Validate()
+			if tc.diags == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var diags Diagnostics
+			require.ErrorAs(t, err, &diags)
+			assert.Equal(t, tc.diags, diags)
+		})
+	}
+}
+
+func TestDiagnosticsError(t *testing.T) {
+	diags := Diagnostics{
+		{Resource: "example", Field: "DisplayName", Message: "must not be empty"},
+		{Resource: "example", Message: "something is wrong"},
+	}
+
+	assert.EqualError(t, diags, "example: DisplayName: must not be empty\nexample: something is wrong")
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// RedactedValue replaces the value of secret fields in FieldViolations.
+const RedactedValue = "[REDACTED]"
+
+var validationMessagePrinter = message.NewPrinter(language.English)
+
+// FieldViolation describes a single input value that failed validation against its schema.
+//
+// FieldViolations are attached to InvalidArgument errors as Connect error details, so that
+// Tempest can highlight the exact form field that failed. Use FieldViolationsFromError to read them.
+type FieldViolation struct {
+	// Field is the JSON pointer to the offending value within the input, for example "/replicas".
+	Field string
+	// Keyword is the path of the JSON schema keyword that failed, for example "type" or "required".
+	Keyword string
+	// Message is a human-readable description of the violation.
+	Message string
+	// Value is the offending value, or nil if the value is missing.
+	// Values of secret fields are replaced by RedactedValue.
+	Value any
+}
+
+// fieldViolations converts the jsonschema.ValidationError tree in err into a flat list of FieldViolations.
+// Values are looked up in the input, and redacted when they belong to a secret property of the schema
+// or match the value of a secret environment variable.
+func fieldViolations(err error, s *JSONSchema, input map[string]any, env map[string]EnvironmentVariable) []FieldViolation {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+
+	var violations []FieldViolation
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, c := range e.Causes {
+				walk(c)
+			}
+			return
+		}
+
+		// Report missing and unexpected properties against the property itself, rather than its parent.
+		var properties []string
+		var propertyKind func(property string) jsonschema.ErrorKind
+		switch k := e.ErrorKind.(type) {
+		case *kind.Required:
+			properties = k.Missing
+			propertyKind = func(p string) jsonschema.ErrorKind { return &kind.Required{Missing: []string{p}} }
+		case *kind.DependentRequired:
+			properties = k.Missing
+			propertyKind = func(p string) jsonschema.ErrorKind {
+				return &kind.DependentRequired{Prop: k.Prop, Missing: []string{p}}
+			}
+		case *kind.AdditionalProperties:
+			properties = k.Properties
+			propertyKind = func(p string) jsonschema.ErrorKind { return &kind.AdditionalProperties{Properties: []string{p}} }
+		}
+
+		if len(properties) > 0 {
+			for _, p := range properties {
+				location := append(append([]string(nil), e.InstanceLocation...), p)
+				violations = append(violations, newFieldViolation(location, propertyKind(p), s, input, env))
+			}
+			return
+		}
+
+		violations = append(violations, newFieldViolation(e.InstanceLocation, e.ErrorKind, s, input, env))
+	}
+	walk(verr)
+
+	return violations
+}
+
+func newFieldViolation(location []string, k jsonschema.ErrorKind, s *JSONSchema, input map[string]any, env map[string]EnvironmentVariable) FieldViolation {
+	v := FieldViolation{
+		Field:   jsonPointer(location),
+		Keyword: strings.Join(k.KeywordPath(), "/"),
+		Message: k.LocalizedString(validationMessagePrinter),
+	}
+
+	value, ok := valueAt(input, location)
+	if !ok {
+		return v
+	}
+
+	if isRedacted(location, value, s, env) {
+		v.Value = RedactedValue
+		v.Message = redactedMessage(k)
+		return v
+	}
+
+	v.Value = value
+	return v
+}
+
+// isRedacted reports whether the value at the location belongs to a secret property of the schema,
+// or contains the value of a secret environment variable.
+func isRedacted(location []string, value any, s *JSONSchema, env map[string]EnvironmentVariable) bool {
+	return (len(location) > 0 && s.isSecretProperty(location[0])) || isSecretValue(value, env)
+}
+
+// redactedMessage describes the violation from the keyword and its parameters only, as the messages
+// of the jsonschema package include the offending value, or its length.
+func redactedMessage(k jsonschema.ErrorKind) string {
+	p := validationMessagePrinter
+	switch k := k.(type) {
+	case *kind.Type, *kind.Enum, *kind.Const:
+		// These messages only mention the expected types and values.
+		return k.LocalizedString(p)
+	case *kind.Format:
+		return p.Sprintf("value is not valid %s", k.Want)
+	case *kind.Pattern:
+		return p.Sprintf("value does not match pattern %q", k.Want)
+	case *kind.MinLength:
+		return p.Sprintf("minLength: want %d", k.Want)
+	case *kind.MaxLength:
+		return p.Sprintf("maxLength: want %d", k.Want)
+	case *kind.Minimum:
+		return p.Sprintf("minimum: want %s", k.Want.RatString())
+	case *kind.Maximum:
+		return p.Sprintf("maximum: want %s", k.Want.RatString())
+	case *kind.ExclusiveMinimum:
+		return p.Sprintf("exclusiveMinimum: want %s", k.Want.RatString())
+	case *kind.ExclusiveMaximum:
+		return p.Sprintf("exclusiveMaximum: want %s", k.Want.RatString())
+	case *kind.MultipleOf:
+		return p.Sprintf("multipleOf: want %s", k.Want.RatString())
+	default:
+		return p.Sprintf("'%s' failed", strings.Join(k.KeywordPath(), "/"))
+	}
+}
+
+// valueAt returns the value at the location within the input.
+func valueAt(input map[string]any, location []string) (any, bool) {
+	var v any = input
+	for _, token := range location {
+		switch val := v.(type) {
+		case map[string]any:
+			next, ok := val[token]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, false
+			}
+			v = val[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// isSecretValue reports whether the value contains the value of a secret environment variable.
+func isSecretValue(v any, env map[string]EnvironmentVariable) bool {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return false
+	}
+
+	for _, e := range env {
+		if isSecretVariable(e) && strings.Contains(s, e.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSecretVariable reports whether the environment variable has a value that must never be echoed back.
+func isSecretVariable(e EnvironmentVariable) bool {
+	if e.Value == "" {
+		return false
+	}
+
+	switch e.Type {
+	case ENVIRONMENT_VARIABLE_TYPE_SECRET, ENVIRONMENT_VARIABLE_TYPE_PRIVATE_KEY, ENVIRONMENT_VARIABLE_TYPE_CERTIFICATE:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonPointer encodes the location as a JSON pointer, as defined in RFC 6901.
+func jsonPointer(location []string) string {
+	var sb strings.Builder
+	for _, token := range location {
+		sb.WriteByte('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+
+	return sb.String()
+}
+
+// invalidInputError returns an InvalidArgument error for an input that failed schema validation,
+// with the FieldViolations attached as an error detail.
+// The message of the error is built from the redacted FieldViolations, rather than from the
+// jsonschema error, which includes the offending values.
+func invalidInputError(msg string, err error, s *JSONSchema, input map[string]any, env map[string]EnvironmentVariable) *connect.Error {
+	violations := fieldViolations(err, s, input, env)
+	if len(violations) == 0 {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %w", msg, err))
+	}
+
+	cerr := connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %w", msg, FieldViolations(violations)))
+	if detail, err := fieldViolationsDetail(violations); err == nil {
+		cerr.AddDetail(detail)
+	}
+
+	return cerr
+}
+
+// invalidOutputError returns an Internal error for an output that failed schema validation.
+// Like invalidInputError, its message is built from the redacted FieldViolations.
+func invalidOutputError(msg string, err error, s *JSONSchema, output map[string]any) *connect.Error {
+	violations := fieldViolations(err, s, output, nil)
+	if len(violations) == 0 {
+		return connect.NewError(connect.CodeInternal, fmt.Errorf("%s: %w", msg, err))
+	}
+
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("%s: %w", msg, FieldViolations(violations)))
+}
+
+// fieldViolationsDetail encodes the FieldViolations as a Connect error detail.
+// The detail is a google.protobuf.Struct with a "field_violations" list.
+func fieldViolationsDetail(violations []FieldViolation) (*connect.ErrorDetail, error) {
+	list := make([]any, 0, len(violations))
+	for _, v := range violations {
+		m := map[string]any{
+			"field":   v.Field,
+			"keyword": v.Keyword,
+			"message": v.Message,
+		}
+		if v.Value != nil {
+			m["value"] = v.Value
+		}
+		list = append(list, m)
+	}
+
+	s, err := structpb.NewStruct(map[string]any{
+		"field_violations": list,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("convert field violations to struct: %w", err)
+	}
+
+	return connect.NewErrorDetail(s)
+}
+
+// FieldViolationsFromError returns the FieldViolations attached to a Connect error returned by
+// the App, or nil if there are none.
+func FieldViolationsFromError(err error) []FieldViolation {
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		return nil
+	}
+
+	var violations []FieldViolation
+	for _, d := range cerr.Details() {
+		msg, err := d.Value()
+		if err != nil {
+			continue
+		}
+
+		s, ok := msg.(*structpb.Struct)
+		if !ok {
+			continue
+		}
+
+		list, ok := s.AsMap()["field_violations"].([]any)
+		if !ok {
+			continue
+		}
+
+		for _, item := range list {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			field, _ := m["field"].(string)
+			keyword, _ := m["keyword"].(string)
+			message, _ := m["message"].(string)
+			violations = append(violations, FieldViolation{
+				Field:   field,
+				Keyword: keyword,
+				Message: message,
+				Value:   m["value"],
+			})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+var violationsSchema = []byte(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"properties": {
+		"name": {"type": "string", "minLength": 3},
+		"replicas": {"type": "integer"},
+		"password": {"type": "string", "writeOnly": true, "minLength": 12},
+		"token": {"type": "string", "maxLength": 4},
+		"zones": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["name", "region"],
+	"additionalProperties": false
+}`)
+
+func TestFieldViolations(t *testing.T) {
+	schema := MustParseJSONSchema(violationsSchema)
+	input := map[string]any{
+		"name":     "ab",
+		"replicas": "three",
+		"password": "hunter2",
+		"token":    "Bearer s3cr3t",
+		"zones":    []any{"us-east-1a", float64(2)},
+		"extra":    true,
+	}
+	env := map[string]EnvironmentVariable{
+		"API_TOKEN": {Key: "API_TOKEN", Value: "s3cr3t", Type: ENVIRONMENT_VARIABLE_TYPE_SECRET},
+		"REGION":    {Key: "REGION", Value: "ab", Type: ENVIRONMENT_VARIABLE_TYPE_VAR},
+	}
+
+	err := schema.Validate(input)
+	require.Error(t, err)
+
+	violations := fieldViolations(err, schema, input, env)
+
+	byField := make(map[string]FieldViolation, len(violations))
+	for _, v := range violations {
+		byField[v.Field] = v
+	}
+
+	assert.Len(t, byField, 7)
+	assert.Equal(t, "ab", byField["/name"].Value)
+	assert.Equal(t, "minLength", byField["/name"].Keyword)
+	assert.Equal(t, "three", byField["/replicas"].Value)
+	assert.Equal(t, "type", byField["/replicas"].Keyword)
+	assert.Equal(t, RedactedValue, byField["/password"].Value)
+	assert.Equal(t, RedactedValue, byField["/token"].Value)
+	assert.Equal(t, float64(2), byField["/zones/1"].Value)
+	assert.Equal(t, "required", byField["/region"].Keyword)
+	assert.Nil(t, byField["/region"].Value)
+	assert.Equal(t, "additionalProperties", byField["/extra"].Keyword)
+	assert.Equal(t, true, byField["/extra"].Value)
+}
+
+func TestFieldViolationsNotValidationError(t *testing.T) {
+	assert.Nil(t, fieldViolations(assert.AnError, MustParseJSONSchema(violationsSchema), nil, nil))
+	assert.Nil(t, FieldViolationsFromError(assert.AnError))
+}
+
+func TestJSONPointer(t *testing.T) {
+	assert.Equal(t, "", jsonPointer(nil))
+	assert.Equal(t, "/a~1b/c~0d/0", jsonPointer([]string{"a/b", "c~d", "0"}))
+}
+
+func TestExecuteResourceOperationFieldViolations(t *testing.T) {
+	rd := generateRD(nil)
+	rd.CreateFn(simpleOpFn, MustParseJSONSchema(violationsSchema))
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	_, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource: &appv1.Resource{
+			Type: "example",
+		},
+		Input: mustNewStruct(map[string]any{
+			"name":     "example",
+			"password": "short",
+		}),
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
+	}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	assert.ElementsMatch(t, []FieldViolation{
+		{
+			Field:   "/password",
+			Keyword: "minLength",
+			Message: "minLength: want 12",
+			Value:   RedactedValue,
+		},
+		{
+			Field:   "/region",
+			Keyword: "required",
+			Message: "missing property 'region'",
+		},
+	}, FieldViolationsFromError(err))
+}
+
+func TestSecretValuesNotLeaked(t *testing.T) {
+	const secret = "hunter2-secret"
+
+	schema := MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"password": {"type": "string", "writeOnly": true, "pattern": "^[A-Z]+$"},
+			"api_key": {"type": "string", "x-tempest-secret": true, "format": "email"},
+			"pin": {"type": "integer", "x-tempest-secret": true, "maximum": 9999},
+			"note": {"type": "string", "maxLength": 4}
+		}
+	}`))
+	env := []*appv1.EnvironmentVariable{
+		{Key: "NOTE_TOKEN", Value: secret + "-env", Type: appv1.EnvironmentVariableType_ENVIRONMENT_VARIABLE_TYPE_SECRET},
+	}
+
+	testCases := []struct {
+		desc     string
+		validate ValidateFunc
+		input    map[string]any
+	}{
+		{
+			desc: "ERR - Schema",
+			input: map[string]any{
+				"password": secret,
+				"api_key":  secret,
+				"pin":      float64(123456),
+				"note":     "token " + secret + "-env",
+			},
+		},
+		{
+			desc: "ERR - ValidateFunc",
+			validate: func(_ context.Context, req *OperationRequest) error {
+				return FieldViolations{
+					{Field: "/password", Message: "'" + req.Input["password"].(string) + "' was found in a breach"},
+					{Field: "/note", Message: "note can't mention " + secret + "-env"},
+				}
+			},
+			input: map[string]any{
+				"password": "ABC" + secret,
+				"note":     "ok",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rd := generateRD(nil)
+			rd.CreateFn(simpleOpFn, schema)
+			if tc.validate != nil {
+				rd.CreateValidateFn(tc.validate)
+			}
+			app := &App{
+				resourceDefinitions: []ResourceDefinition{rd},
+			}
+
+			_, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+				Resource:             &appv1.Resource{Type: "example"},
+				Input:                mustNewStruct(tc.input),
+				Operation:            appv1.ResourceOperation_RESOURCE_OPERATION_CREATE,
+				EnvironmentVariables: env,
+			}))
+			require.Error(t, err)
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			assert.NotContains(t, err.Error(), secret)
+			assert.NotContains(t, err.Error(), "123456")
+
+			violations := FieldViolationsFromError(err)
+			require.NotEmpty(t, violations)
+			for _, v := range violations {
+				assert.NotContains(t, v.Message, secret, v.Field)
+				assert.NotContains(t, v.Message, "123456", v.Field)
+				if v.Value != nil {
+					assert.Equal(t, RedactedValue, v.Value, v.Field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// BigNumberMode controls how integers that can't be represented exactly as a float64 are normalized.
+type BigNumberMode int
+
+const (
+	// BigNumberAsFloat converts big numbers to the nearest float64. Precision may be lost.
+	BigNumberAsFloat BigNumberMode = iota
+	// BigNumberAsString converts big numbers to their decimal string representation.
+	BigNumberAsString
+	// BigNumberError fails the normalization of big numbers.
+	BigNumberError
+)
+
+// maxExactFloat is the largest integer up to which every integer can be represented exactly as a float64.
+const maxExactFloat = 1 << 53
+
+// ValueOptions configures how Go values in Resource.Properties and action outputs are normalized
+// to JSON values before validation and conversion to protobuf.
+type ValueOptions struct {
+	// TimeFormat is the layout used to format time.Time values in maps, slices and pointers.
+	// Defaults to time.RFC3339Nano. time.Time fields of structs are formatted by their JSON encoding.
+	TimeFormat string
+	// BigNumbers controls how integers beyond the float64 precision, *big.Int, *big.Float and *big.Rat are normalized.
+	BigNumbers BigNumberMode
+}
+
+// NormalizeValue converts an arbitrary Go value into the JSON value types used by the SDK:
+// nil, bool, float64, string, []any and map[string]any.
+//
+// Typed slices and maps are converted element by element, structs and custom types are converted through
+// their JSON encoding, and time and big number values are handled according to the options.
+// This lets handlers return objects from upstream SDKs directly in Resource.Properties.
+func NormalizeValue(v any, opts ValueOptions) (any, error) {
+	n := valueNormalizer{opts: opts}
+	if n.opts.TimeFormat == "" {
+		n.opts.TimeFormat = time.RFC3339Nano
+	}
+
+	return n.normalize(reflect.ValueOf(v))
+}
+
+// normalizeProperties normalizes a map of properties, as returned in Resource.Properties or ActionResponse.Output.
+func normalizeProperties(properties map[string]any, opts ValueOptions) (map[string]any, error) {
+	if properties == nil {
+		return nil, nil
+	}
+
+	v, err := NormalizeValue(properties, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(map[string]any), nil
+}
+
+type valueNormalizer struct {
+	opts ValueOptions
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	bigIntType        = reflect.TypeFor[big.Int]()
+	bigFloatType      = reflect.TypeFor[big.Float]()
+	bigRatType        = reflect.TypeFor[big.Rat]()
+	jsonNumberType    = reflect.TypeFor[json.Number]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (n *valueNormalizer) normalize(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		return v.Interface().(time.Time).Format(n.opts.TimeFormat), nil
+	case jsonNumberType:
+		return n.number(v.Interface().(json.Number).String())
+	case bigIntType, bigFloatType, bigRatType:
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return n.bigNumber(p)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Pointer {
+			switch v.Type().Elem() {
+			case timeType, bigIntType, bigFloatType, bigRatType:
+				return n.normalize(v.Elem())
+			}
+		}
+		if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+			return n.marshaled(v)
+		}
+		return n.normalize(v.Elem())
+	}
+
+	// Custom encodings take precedence over the kind of the value, as in encoding/json.
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) ||
+		reflect.PointerTo(v.Type()).Implements(jsonMarshalerType) {
+		return n.marshaled(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i > maxExactFloat || i < -maxExactFloat {
+			return n.number(strconv.FormatInt(i, 10))
+		}
+		return float64(i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > maxExactFloat {
+			return n.number(strconv.FormatUint(u, 10))
+		}
+		return float64(u), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("unsupported value %v", f)
+		}
+		return f, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		s := make([]any, v.Len())
+		for i := range v.Len() {
+			e, err := n.normalize(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			s[i] = e
+		}
+		return s, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := mapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			e, err := n.normalize(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			m[k] = e
+		}
+		return m, nil
+	case reflect.Struct:
+		return n.marshaled(v)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// marshaled normalizes a value through its JSON encoding.
+func (n *valueNormalizer) marshaled(v reflect.Value) (any, error) {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", v.Type(), err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var decoded any
+	if err := d.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", v.Type(), err)
+	}
+
+	return n.decoded(decoded)
+}
+
+// decoded converts the json.Number values of a decoded JSON value.
+func (n *valueNormalizer) decoded(v any) (any, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return n.number(val.String())
+	case []any:
+		for i, e := range val {
+			d, err := n.decoded(e)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			val[i] = d
+		}
+		return val, nil
+	case map[string]any:
+		for k, e := range val {
+			d, err := n.decoded(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			val[k] = d
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
+
+// number converts the decimal representation of a number, applying the BigNumberMode
+// to integers that can't be represented exactly as a float64.
+func (n *valueNormalizer) number(s string) (any, error) {
+	if i, ok := new(big.Int).SetString(s, 10); ok && i.CmpAbs(big.NewInt(maxExactFloat)) > 0 {
+		return n.big(s)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s: %w", s, err)
+	}
+
+	return f, nil
+}
+
+func (n *valueNormalizer) bigNumber(v reflect.Value) (any, error) {
+	var s string
+	switch b := v.Interface().(type) {
+	case *big.Int:
+		if b.IsInt64() && b.Int64() <= maxExactFloat && b.Int64() >= -maxExactFloat {
+			return float64(b.Int64()), nil
+		}
+		s = b.String()
+	case *big.Float:
+		s = b.Text('g', -1)
+	case *big.Rat:
+		if f, exact := b.Float64(); exact {
+			return f, nil
+		}
+		s = b.FloatString(20)
+	}
+
+	return n.big(s)
+}
+
+func (n *valueNormalizer) big(s string) (any, error) {
+	switch n.opts.BigNumbers {
+	case BigNumberAsString:
+		return s, nil
+	case BigNumberError:
+		return nil, fmt.Errorf("number %s can't be represented exactly", s)
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return nil, fmt.Errorf("invalid number %s: %w", s, err)
+		}
+		return f, nil
+	}
+}
+
+// mapKey converts a map key to a string, following the rules of encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("marshal map key: %w", err)
+		}
+		return string(b), nil
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("unsupported map key type %s", k.Type())
+	}
+}
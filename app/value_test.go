@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+type upstreamTags []string
+
+type upstreamBucket struct {
+	Name      string            `json:"name"`
+	CreatedAt time.Time         `json:"created_at"`
+	Size      int64             `json:"size"`
+	Tags      upstreamTags      `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	internal  string
+}
+
+type upstreamStatus int
+
+func (s upstreamStatus) MarshalText() ([]byte, error) {
+	return []byte([]string{"pending", "ready"}[s]), nil
+}
+
+func TestNormalizeValue(t *testing.T) {
+	created := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	bigInt := int64(1<<53 + 1)
+
+	testCases := []struct {
+		desc     string
+		value    any
+		opts     ValueOptions
+		expected any
+		err      string
+	}{
+		{
+			desc:     "OK - JSON Values",
+			value:    map[string]any{"a": "b", "c": 1.5, "d": true, "e": nil, "f": []any{"g"}},
+			expected: map[string]any{"a": "b", "c": 1.5, "d": true, "e": nil, "f": []any{"g"}},
+		},
+		{
+			desc:     "OK - Typed Slices And Integers",
+			value:    map[string]any{"zones": []string{"a", "b"}, "count": int32(3), "ids": [2]uint8{1, 2}},
+			expected: map[string]any{"zones": []any{"a", "b"}, "count": float64(3), "ids": []any{float64(1), float64(2)}},
+		},
+		{
+			desc:  "OK - Struct",
+			value: map[string]any{"bucket": &upstreamBucket{Name: "logs", CreatedAt: created, Size: 10, Tags: upstreamTags{"prod"}, internal: "x"}},
+			expected: map[string]any{"bucket": map[string]any{
+				"name":       "logs",
+				"created_at": "2024-01-02T15:04:05Z",
+				"size":       float64(10),
+				"tags":       []any{"prod"},
+			}},
+		},
+		{
+			desc:     "OK - Time Format",
+			value:    map[string]any{"created": created, "updated": &created},
+			opts:     ValueOptions{TimeFormat: time.DateOnly},
+			expected: map[string]any{"created": "2024-01-02", "updated": "2024-01-02"},
+		},
+		{
+			desc:     "OK - Text Marshaler And Map Keys",
+			value:    map[string]any{"status": upstreamStatus(1), "ports": map[int]string{80: "http"}},
+			expected: map[string]any{"status": "ready", "ports": map[string]any{"80": "http"}},
+		},
+		{
+			desc:     "OK - Big Numbers As Float",
+			value:    map[string]any{"id": bigInt, "n": json.Number("12")},
+			expected: map[string]any{"id": float64(bigInt), "n": float64(12)},
+		},
+		{
+			desc:     "OK - Big Numbers As String",
+			value:    map[string]any{"id": bigInt, "big": new(big.Int).Lsh(big.NewInt(1), 70), "small": big.NewInt(7), "rat": big.NewRat(1, 3)},
+			opts:     ValueOptions{BigNumbers: BigNumberAsString},
+			expected: map[string]any{"id": "9007199254740993", "big": "1180591620717411303424", "small": float64(7), "rat": "0.33333333333333333333"},
+		},
+		{
+			desc:  "ERR - Big Numbers",
+			value: map[string]any{"bucket": upstreamBucket{Size: bigInt}},
+			opts:  ValueOptions{BigNumbers: BigNumberError},
+			err:   "bucket: size: number 9007199254740993 can't be represented exactly",
+		},
+		{
+			desc:  "ERR - NaN",
+			value: map[string]any{"ratio": math.NaN()},
+			err:   "ratio: unsupported value NaN",
+		},
+		{
+			desc:  "ERR - Unsupported Type",
+			value: map[string]any{"fn": func() {}},
+			err:   "fn: unsupported type func()",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			v, err := NormalizeValue(tc.value, tc.opts)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func TestExecuteResourceOperationNormalizesProperties(t *testing.T) {
+	rd := generateRD(nil)
+	rd.PropertiesSchema = MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {
+			"zones": {"type": "array", "items": {"type": "string"}},
+			"created_at": {"type": "string", "format": "date-time"},
+			"size": {"type": "integer"}
+		}
+	}`))
+	rd.ReadFn(func(_ context.Context, req *OperationRequest) (*OperationResponse, error) {
+		return &OperationResponse{
+			Resource: &Resource{
+				ExternalID: req.Resource.ExternalID,
+				Properties: map[string]any{
+					"zones":      []string{"a", "b"},
+					"created_at": time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+					"size":       int64(10),
+				},
+			},
+		}, nil
+	})
+
+	app := &App{
+		resourceDefinitions: []ResourceDefinition{rd},
+	}
+
+	res, err := app.ExecuteResourceOperation(context.Background(), connect.NewRequest(&appv1.ExecuteResourceOperationRequest{
+		Resource:  &appv1.Resource{Type: "example", ExternalId: "123"},
+		Operation: appv1.ResourceOperation_RESOURCE_OPERATION_READ,
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"zones":      []any{"a", "b"},
+		"created_at": "2024-01-02T15:04:05Z",
+		"size":       float64(10),
+	}, res.Msg.Resource.Properties.AsMap())
+}
@@ -0,0 +1,179 @@
+// Package appdoc generates the documentation of the resource types of a Tempest app, from the
+// ResourceDescriptors returned by Describe.
+//
+// A page documents a resource type with:
+//
+//   - its description, lifecycle stage, supported operations and links
+//   - a table of the properties of its PropertiesSchema
+//   - tables of the create and update inputs
+//   - its actions, with tables of their inputs and outputs
+//   - its InstructionsMarkdown, with the {{ resource.<property name> }} variables left as they are
+//
+// Pages are Markdown or standalone HTML. WriteSite writes a page per resource type and an index:
+//
+//	descriptors, err := appdoc.FromApp(ctx, a)
+//	if err != nil {
+//		return err
+//	}
+//	err = appdoc.WriteSite("docs", descriptors, appdoc.Options{Title: "Fake Cloud"})
+//
+// HTML pages only render the subset of Markdown used by the generated pages and common instructions:
+// headings, paragraphs, lists, tables, fenced code blocks, code spans, emphasis and links.
+// Raw HTML in descriptions and instructions is escaped.
+package appdoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// Format is the format of the generated pages.
+type Format int
+
+const (
+	FormatMarkdown Format = iota // markdown
+	FormatHTML                   // html
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatMarkdown:
+		return "markdown"
+	case FormatHTML:
+		return "html"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// ext returns the file extension of the pages in the format.
+func (f Format) ext() string {
+	if f == FormatHTML {
+		return ".html"
+	}
+
+	return ".md"
+}
+
+// Options configures the generated pages.
+type Options struct {
+	// Title is the title of the index. Defaults to "Resource types".
+	Title string
+	// Format is the format of the pages. Defaults to FormatMarkdown.
+	Format Format
+	// Examples adds an example of every input, generated with JSONSchema.MaximalExample.
+	Examples bool
+}
+
+func (o Options) title() string {
+	if o.Title == "" {
+		return "Resource types"
+	}
+
+	return o.Title
+}
+
+// FromApp returns the ResourceDescriptors of the App, by calling Describe in-process.
+func FromApp(ctx context.Context, a *app.App, opts ...app.SchemaOption) ([]*app.ResourceDescriptor, error) {
+	return app.NewServiceClient(a).Describe(ctx, opts...)
+}
+
+// FromDescribeResponse returns the ResourceDescriptors of a DescribeResponse, for example one
+// stored by a previous call to Describe.
+func FromDescribeResponse(res *appv1.DescribeResponse, opts ...app.SchemaOption) ([]*app.ResourceDescriptor, error) {
+	return app.DescriptorsFromProto(res, opts...)
+}
+
+// Page returns the page that documents the resource type.
+func Page(d *app.ResourceDescriptor, opts Options) ([]byte, error) {
+	if d == nil {
+		return nil, errors.New("resource descriptor is required")
+	}
+
+	md, err := pageMarkdown(d, opts)
+	if err != nil {
+		return nil, fmt.Errorf("resource type %s: %w", d.Type, err)
+	}
+
+	return render(title(d), md, opts.Format)
+}
+
+// Index returns the page that lists the resource types, linking to the pages written by WriteSite.
+func Index(descriptors []*app.ResourceDescriptor, opts Options) ([]byte, error) {
+	md, err := indexMarkdown(descriptors, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return render(opts.title(), md, opts.Format)
+}
+
+// WriteSite writes the index and a page per resource type to dir, which is created if needed.
+// The pages are named after the resource types, for example fakecloud_object.md.
+func WriteSite(dir string, descriptors []*app.ResourceDescriptor, opts Options) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	index, err := Index(descriptors, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index"+opts.Format.ext()), index, 0o644); err != nil {
+		return err
+	}
+
+	for _, d := range descriptors {
+		page, err := Page(d, opts)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, pageName(d.Type, opts.Format)), page, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageName returns the file name of the page of the resource type.
+func pageName(resourceType string, f Format) string {
+	return resourceType + f.ext()
+}
+
+func validType(resourceType string) error {
+	if resourceType == "" {
+		return errors.New("resource type is required")
+	}
+	if strings.ContainsAny(resourceType, `/\`) || resourceType == "." || resourceType == ".." || resourceType == "index" {
+		return fmt.Errorf("resource type %q can't be used as a file name", resourceType)
+	}
+
+	return nil
+}
+
+// title returns the display name of the resource type, or its type when it has none.
+func title(d *app.ResourceDescriptor) string {
+	if d.DisplayName != "" {
+		return d.DisplayName
+	}
+
+	return d.Type
+}
+
+// render returns the Markdown, or the HTML document with the title that it renders to.
+func render(title, md string, f Format) ([]byte, error) {
+	if f == FormatHTML {
+		return htmlDocument(title, md)
+	}
+
+	return []byte(md), nil
+}
@@ -0,0 +1,262 @@
+package appdoc
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/sdk-go/app"
+	"github.com/tempestdx/sdk-go/examples/fakecloud"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func newApp() *app.App {
+	return app.New(app.WithResourceDefinition(fakecloud.ResourceDefinition(fakecloud.New())))
+}
+
+func descriptors(t *testing.T) []*app.ResourceDescriptor {
+	t.Helper()
+
+	ds, err := FromApp(context.Background(), newApp())
+	require.NoError(t, err)
+
+	return ds
+}
+
+func TestPage(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		opts   Options
+		golden string
+	}{
+		{
+			desc:   "OK - Markdown",
+			opts:   Options{Examples: true},
+			golden: "fakecloud_object.md.golden",
+		},
+		{
+			desc:   "OK - HTML",
+			opts:   Options{Format: FormatHTML},
+			golden: "fakecloud_object.html.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := Page(descriptors(t)[0], tc.opts)
+			require.NoError(t, err)
+
+			assertGolden(t, tc.golden, page)
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	ds := append(descriptors(t), &app.ResourceDescriptor{
+		Type:        "fakecloud_bucket",
+		Description: "A bucket | of objects.",
+	})
+
+	testCases := []struct {
+		desc        string
+		descriptors []*app.ResourceDescriptor
+		opts        Options
+		want        string
+	}{
+		{
+			desc:        "OK - Markdown",
+			descriptors: ds,
+			opts:        Options{Title: "Fake Cloud"},
+			want: "# Fake Cloud\n\n" +
+				"| Resource type | Type | Lifecycle stage | Description |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| [Fake Cloud Object](fakecloud_object.md) | `fakecloud_object` | operate | A key/value object stored in the in-memory Fake Cloud. |\n" +
+				"| [fakecloud_bucket](fakecloud_bucket.md) | `fakecloud_bucket` |  | A bucket \\| of objects. |\n",
+		},
+		{
+			desc: "OK - No Resource Types",
+			want: "# Resource types\n\nNo resource types.\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			index, err := Index(tc.descriptors, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(index))
+		})
+	}
+
+	t.Run("OK - HTML", func(t *testing.T) {
+		index, err := Index(ds, Options{Format: FormatHTML})
+		require.NoError(t, err)
+		assert.Contains(t, string(index), "<title>Resource types</title>")
+		assert.Contains(t, string(index), `<td><a href="fakecloud_object.html">Fake Cloud Object</a></td>`)
+		assert.Contains(t, string(index), "<td>A bucket | of objects.</td>")
+	})
+}
+
+func TestIndexErrors(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		descriptors []*app.ResourceDescriptor
+		err         string
+	}{
+		{
+			desc:        "ERR - Nil Descriptor",
+			descriptors: []*app.ResourceDescriptor{nil},
+			err:         "resource descriptor is required",
+		},
+		{
+			desc:        "ERR - No Type",
+			descriptors: []*app.ResourceDescriptor{{}},
+			err:         "resource type is required",
+		},
+		{
+			desc:        "ERR - Path Type",
+			descriptors: []*app.ResourceDescriptor{{Type: "../object"}},
+			err:         `resource type "../object" can't be used as a file name`,
+		},
+		{
+			desc:        "ERR - Index Type",
+			descriptors: []*app.ResourceDescriptor{{Type: "index"}},
+			err:         `resource type "index" can't be used as a file name`,
+		},
+		{
+			desc:        "ERR - Duplicate Type",
+			descriptors: []*app.ResourceDescriptor{{Type: "object"}, {Type: "object"}},
+			err:         "resource type object is described more than once",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := Index(tc.descriptors, Options{})
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestWriteSite(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		opts  Options
+		files []string
+	}{
+		{
+			desc:  "OK - Markdown",
+			files: []string{"fakecloud_object.md", "index.md"},
+		},
+		{
+			desc:  "OK - HTML",
+			opts:  Options{Format: FormatHTML},
+			files: []string{"fakecloud_object.html", "index.html"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "docs")
+			ds := descriptors(t)
+			require.NoError(t, WriteSite(dir, ds, tc.opts))
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			var files []string
+			for _, e := range entries {
+				files = append(files, e.Name())
+			}
+			assert.Equal(t, tc.files, files)
+
+			page, err := Page(ds[0], tc.opts)
+			require.NoError(t, err)
+			written, err := os.ReadFile(filepath.Join(dir, tc.files[0]))
+			require.NoError(t, err)
+			assert.Equal(t, string(page), string(written))
+		})
+	}
+}
+
+func TestFromDescribeResponse(t *testing.T) {
+	res, err := newApp().Describe(context.Background(), connect.NewRequest(&appv1.DescribeRequest{}))
+	require.NoError(t, err)
+
+	ds, err := FromDescribeResponse(res.Msg)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+
+	page, err := Page(ds[0], Options{Examples: true})
+	require.NoError(t, err)
+	assertGolden(t, "fakecloud_object.md.golden", page)
+}
+
+func TestPageSections(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		descriptor *app.ResourceDescriptor
+		opts       Options
+		want       string
+	}{
+		{
+			desc: "OK - Minimal",
+			descriptor: &app.ResourceDescriptor{
+				Type: "object",
+			},
+			want: "# object\n\n- Type: `object`\n- Operations: none\n",
+		},
+		{
+			desc: "OK - Empty Schemas",
+			descriptor: &app.ResourceDescriptor{
+				Type:              "object",
+				CreateSupported:   true,
+				CreateInputSchema: app.MustParseJSONSchema([]byte(`{"type": "object"}`)),
+				Actions: []app.ActionDescriptor{
+					{Name: "restart", InputSchema: app.MustParseJSONSchema([]byte(`{"type": "object"}`))},
+				},
+			},
+			opts: Options{Examples: true},
+			want: "# object\n\n- Type: `object`\n- Operations: create\n\n" +
+				"## Create input\n\nNone.\n\n" +
+				"## Actions\n\n### restart\n\n- Name: `restart`\n\n#### Input\n\nNone.\n",
+		},
+		{
+			desc: "OK - Links Without Title Or Type",
+			descriptor: &app.ResourceDescriptor{
+				Type:  "object",
+				Links: []app.Link{{URL: "https://example.com"}},
+			},
+			want: "# object\n\n- Type: `object`\n- Operations: none\n\n## Links\n\n- [https://example.com](https://example.com)\n",
+		},
+		{
+			desc: "OK - Instructions Already Nested",
+			descriptor: &app.ResourceDescriptor{
+				Type:                 "object",
+				InstructionsMarkdown: "\n#### Connect\n\nRun it.\n",
+			},
+			want: "# object\n\n- Type: `object`\n- Operations: none\n\n## Instructions\n\n#### Connect\n\nRun it.\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := Page(tc.descriptor, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(page))
+		})
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
@@ -0,0 +1,380 @@
+package appdoc
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+var documentTemplate = template.Must(template.New("document").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; vertical-align: top; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+</style>
+</head>
+<body>
+<main>
+{{ .Body }}</main>
+</body>
+</html>
+`))
+
+// htmlDocument returns a standalone HTML document with the title and the rendered Markdown.
+func htmlDocument(title, md string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := documentTemplate.Execute(&buf, struct {
+		Title string
+		Body  template.HTML
+	}{
+		Title: title,
+		// renderHTML escapes the text of the Markdown.
+		Body: template.HTML(renderHTML(md)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderHTML renders the blocks of the Markdown: headings, paragraphs, lists, tables and fenced code blocks.
+func renderHTML(md string) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case codeFence(line) != "":
+			i = writeCodeBlock(&b, lines, i)
+		case headingLevel(line) > 0:
+			level := headingLevel(line)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, inline(strings.TrimSpace(line[level:])), level)
+			i++
+		case isTableStart(lines, i):
+			i = writeTable(&b, lines, i)
+		case isListItem(line):
+			i = writeList(&b, lines, i)
+		default:
+			i = writeParagraph(&b, lines, i)
+		}
+	}
+
+	return b.String()
+}
+
+// startsBlock reports whether the line starts a block other than a paragraph.
+func startsBlock(lines []string, i int) bool {
+	return codeFence(lines[i]) != "" || headingLevel(lines[i]) > 0 || isTableStart(lines, i) || isListItem(lines[i])
+}
+
+func writeCodeBlock(b *strings.Builder, lines []string, i int) int {
+	fence := codeFence(lines[i])
+	info := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(lines[i]), fence[:1]))
+	lang, _, _ := strings.Cut(info, " ")
+
+	b.WriteString("<pre><code")
+	if lang != "" {
+		fmt.Fprintf(b, ` class="language-%s"`, html.EscapeString(lang))
+	}
+	b.WriteString(">")
+
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		b.WriteString(html.EscapeString(lines[i]))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+
+	return i
+}
+
+func writeParagraph(b *strings.Builder, lines []string, i int) int {
+	var text []string
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		if len(text) > 0 && startsBlock(lines, i) {
+			break
+		}
+		text = append(text, strings.TrimSpace(lines[i]))
+	}
+	fmt.Fprintf(b, "<p>%s</p>\n", inline(strings.Join(text, "\n")))
+
+	return i
+}
+
+// listItem returns the tag of the list of the item on the line, ul or ol, and the text of the item.
+func listItem(line string) (string, string) {
+	t := strings.TrimLeft(line, " ")
+	if len(line)-len(t) > 3 {
+		return "", ""
+	}
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(t, marker) {
+			return "ul", t[len(marker):]
+		}
+	}
+
+	n := len(t) - len(strings.TrimLeft(t, "0123456789"))
+	if n > 0 && n < 10 && len(t) > n+1 && (t[n] == '.' || t[n] == ')') && t[n+1] == ' ' {
+		return "ol", t[n+2:]
+	}
+
+	return "", ""
+}
+
+func isListItem(line string) bool {
+	tag, _ := listItem(line)
+	return tag != ""
+}
+
+// writeList writes the consecutive items of the same kind of list. Indented lines continue an item.
+func writeList(b *strings.Builder, lines []string, i int) int {
+	tag, _ := listItem(lines[i])
+
+	var items []string
+	for i < len(lines) {
+		line := lines[i]
+		if t, text := listItem(line); t == tag {
+			items = append(items, strings.TrimSpace(text))
+			i++
+			continue
+		} else if t != "" {
+			break
+		}
+
+		if strings.TrimSpace(line) == "" {
+			// A blank line ends the list, unless it is followed by another item.
+			if i+1 < len(lines) {
+				if t, _ := listItem(lines[i+1]); t == tag {
+					i++
+					continue
+				}
+			}
+			break
+		}
+		if !strings.HasPrefix(line, "  ") || startsBlock(lines, i) {
+			break
+		}
+		items[len(items)-1] += "\n" + strings.TrimSpace(line)
+		i++
+	}
+
+	fmt.Fprintf(b, "<%s>\n", tag)
+	for _, item := range items {
+		fmt.Fprintf(b, "<li>%s</li>\n", inline(item))
+	}
+	fmt.Fprintf(b, "</%s>\n", tag)
+
+	return i
+}
+
+// isTableStart reports whether the line is the header row of a table, followed by its delimiter row.
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return false
+	}
+
+	header, delimiter := tableCells(lines[i]), tableCells(lines[i+1])
+	if len(header) != len(delimiter) {
+		return false
+	}
+	for _, c := range delimiter {
+		c = strings.TrimSuffix(strings.TrimPrefix(c, ":"), ":")
+		if c == "" || strings.Trim(c, "-") != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeTable(b *strings.Builder, lines []string, i int) int {
+	header := tableCells(lines[i])
+
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, c := range header {
+		fmt.Fprintf(b, "<th>%s</th>", inline(c))
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for i += 2; i < len(lines) && strings.Contains(lines[i], "|"); i++ {
+		cells := tableCells(lines[i])
+		b.WriteString("<tr>")
+		for j := range header {
+			c := ""
+			if j < len(cells) {
+				c = cells[j]
+			}
+			fmt.Fprintf(b, "<td>%s</td>", inline(c))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+
+	return i
+}
+
+// tableCells splits the row of a table on the pipes that are not escaped, and unescapes the others.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// inline renders the code spans, emphasis, links and backslash escapes of the text, and escapes the rest.
+func inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			end := closingBackticks(s, i+n, n)
+			if end < 0 {
+				b.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(code))
+			i = end + n
+		case strings.HasPrefix(s[i:], "**"):
+			end := strings.Index(s[i+2:], "**")
+			if end <= 0 {
+				b.WriteString("**")
+				i += 2
+				continue
+			}
+			fmt.Fprintf(&b, "<strong>%s</strong>", inline(s[i+2:i+2+end]))
+			i += end + 4
+		case c == '*':
+			end := strings.IndexByte(s[i+1:], '*')
+			if end <= 0 || s[i+1] == ' ' {
+				b.WriteByte('*')
+				i++
+				continue
+			}
+			fmt.Fprintf(&b, "<em>%s</em>", inline(s[i+1:i+1+end]))
+			i += end + 2
+		case c == '[':
+			text, url, n, ok := link(s[i:])
+			if !ok {
+				b.WriteByte('[')
+				i++
+				continue
+			}
+			if safeURL(url) {
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(url), inline(text))
+			} else {
+				b.WriteString(inline(text))
+			}
+			i += n
+		default:
+			b.WriteString(html.EscapeString(s[i : i+1]))
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// closingBackticks returns the index of the next run of exactly n backticks in s from i, or -1.
+func closingBackticks(s string, i, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		start := i + j
+		end := start
+		for end < len(s) && s[end] == '`' {
+			end++
+		}
+		if end-start == n {
+			return start
+		}
+		i = end
+	}
+
+	return -1
+}
+
+// link parses the inline link [text](url) at the start of s, and returns its text, its URL and its length.
+func link(s string) (string, string, int, bool) {
+	bracket := -1
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == ']' {
+			bracket = i
+			break
+		}
+	}
+	if bracket < 0 || bracket+1 >= len(s) || s[bracket+1] != '(' {
+		return "", "", 0, false
+	}
+
+	end := strings.IndexByte(s[bracket+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	dest := strings.TrimSpace(s[bracket+2 : bracket+2+end])
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+
+	return s[1:bracket], dest, bracket + 3 + end, true
+}
+
+// safeURL reports whether the URL is relative, or uses the http, https or mailto scheme.
+func safeURL(url string) bool {
+	i := strings.IndexAny(url, ":/?#")
+	if i < 0 || url[i] != ':' {
+		return true
+	}
+
+	switch strings.ToLower(url[:i]) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+package appdoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderHTML(t *testing.T) {
+	testCases := []struct {
+		desc string
+		md   string
+		want string
+	}{
+		{
+			desc: "OK - Heading And Paragraphs",
+			md:   "## Connect\n\nRun the\ncommand.\n\nThen wait.",
+			want: "<h2>Connect</h2>\n<p>Run the\ncommand.</p>\n<p>Then wait.</p>\n",
+		},
+		{
+			desc: "OK - Lists",
+			md:   "- one\n- two\n  continued\n\n- three\n1. first\n2. second",
+			want: "<ul>\n<li>one</li>\n<li>two\ncontinued</li>\n<li>three</li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			desc: "OK - Paragraph Followed By List",
+			md:   "Steps:\n- one",
+			want: "<p>Steps:</p>\n<ul>\n<li>one</li>\n</ul>\n",
+		},
+		{
+			desc: "OK - Table",
+			md:   "| Name | Description |\n| :--- | ---: |\n| `a\\|b` | x |\n| y |",
+			want: "<table>\n<thead>\n<tr><th>Name</th><th>Description</th></tr>\n</thead>\n<tbody>\n" +
+				"<tr><td><code>a|b</code></td><td>x</td></tr>\n<tr><td>y</td><td></td></tr>\n</tbody>\n</table>\n",
+		},
+		{
+			desc: "OK - Not A Table",
+			md:   "a | b\nc | d",
+			want: "<p>a | b\nc | d</p>\n",
+		},
+		{
+			desc: "OK - Code Block",
+			md:   "```sh\necho <b> && exit\n\n# done\n```\nafter",
+			want: "<pre><code class=\"language-sh\">echo &lt;b&gt; &amp;&amp; exit\n\n# done\n</code></pre>\n<p>after</p>\n",
+		},
+		{
+			desc: "OK - Unclosed Code Block",
+			md:   "~~~\ncode",
+			want: "<pre><code>code\n</code></pre>\n",
+		},
+		{
+			desc: "OK - Raw HTML Escaped",
+			md:   "<script>alert(1)</script>",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, renderHTML(tc.md))
+		})
+	}
+}
+
+func TestInline(t *testing.T) {
+	testCases := []struct {
+		desc string
+		text string
+		want string
+	}{
+		{
+			desc: "OK - Code Span",
+			text: "run `a <b>` now",
+			want: "run <code>a &lt;b&gt;</code> now",
+		},
+		{
+			desc: "OK - Code Span With Backticks",
+			text: "`` a`b ``",
+			want: "<code>a`b</code>",
+		},
+		{
+			desc: "OK - Unclosed Code Span",
+			text: "a ` b",
+			want: "a ` b",
+		},
+		{
+			desc: "OK - Emphasis",
+			text: "**bold** and *em* and 2 * 3",
+			want: "<strong>bold</strong> and <em>em</em> and 2 * 3",
+		},
+		{
+			desc: "OK - Link",
+			text: "see [the **docs**](https://example.com/a?b=1&c=2)",
+			want: `see <a href="https://example.com/a?b=1&amp;c=2">the <strong>docs</strong></a>`,
+		},
+		{
+			desc: "OK - Relative Link",
+			text: "[Object](fakecloud_object.html)",
+			want: `<a href="fakecloud_object.html">Object</a>`,
+		},
+		{
+			desc: "OK - Unsafe Link",
+			text: "[click](javascript:alert(1))",
+			want: "click)",
+		},
+		{
+			desc: "OK - Not A Link",
+			text: "[a] (b)",
+			want: "[a] (b)",
+		},
+		{
+			desc: "OK - Escapes",
+			text: `\*not em\* \[x\] a\b`,
+			want: `*not em* [x] a\b`,
+		},
+		{
+			desc: "OK - Snake Case",
+			text: "created_at and updated_at",
+			want: "created_at and updated_at",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, inline(tc.text))
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package appdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+func indexMarkdown(descriptors []*app.ResourceDescriptor, opts Options) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", opts.title())
+
+	if len(descriptors) == 0 {
+		b.WriteString("No resource types.\n")
+		return b.String(), nil
+	}
+
+	b.WriteString("| Resource type | Type | Lifecycle stage | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	seen := make(map[string]bool, len(descriptors))
+	for _, d := range descriptors {
+		if d == nil {
+			return "", errors.New("resource descriptor is required")
+		}
+		if err := validType(d.Type); err != nil {
+			return "", err
+		}
+		if seen[d.Type] {
+			return "", fmt.Errorf("resource type %s is described more than once", d.Type)
+		}
+		seen[d.Type] = true
+
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s | %s |\n",
+			cell(escapeLinkText(title(d))),
+			pageName(d.Type, opts.Format),
+			codeSpan(d.Type),
+			lifecycleStage(d.LifecycleStage),
+			cell(d.Description),
+		)
+	}
+
+	return b.String(), nil
+}
+
+func pageMarkdown(d *app.ResourceDescriptor, opts Options) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title(d))
+	if d.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", d.Description)
+	}
+
+	fmt.Fprintf(&b, "- Type: %s\n", codeSpan(d.Type))
+	if stage := lifecycleStage(d.LifecycleStage); stage != "" {
+		fmt.Fprintf(&b, "- Lifecycle stage: %s\n", stage)
+	}
+	fmt.Fprintf(&b, "- Operations: %s\n", strings.Join(operations(d), ", "))
+
+	if len(d.Links) > 0 {
+		b.WriteString("\n## Links\n\n")
+		for _, l := range d.Links {
+			text := l.Title
+			if text == "" {
+				text = l.URL
+			}
+			fmt.Fprintf(&b, "- [%s](%s)", escapeLinkText(text), l.URL)
+			if l.Type != app.LinkTypeUnspecified {
+				fmt.Fprintf(&b, " (%s)", l.Type)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	sections := []struct {
+		heading string
+		schema  *app.JSONSchema
+		example bool
+	}{
+		{"Properties", d.PropertiesSchema, false},
+		{"Create input", d.CreateInputSchema, opts.Examples},
+		{"Update input", d.UpdateInputSchema, opts.Examples},
+	}
+	for _, s := range sections {
+		if s.schema == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", s.heading)
+		if err := writeSchema(&b, s.schema, s.example); err != nil {
+			return "", fmt.Errorf("%s: %w", strings.ToLower(s.heading), err)
+		}
+	}
+
+	if len(d.Actions) > 0 {
+		b.WriteString("\n## Actions\n")
+		for _, a := range d.Actions {
+			if err := writeAction(&b, a, opts); err != nil {
+				return "", fmt.Errorf("action %s: %w", a.Name, err)
+			}
+		}
+	}
+
+	if strings.TrimSpace(d.InstructionsMarkdown) != "" {
+		b.WriteString("\n## Instructions\n\n")
+		b.WriteString(strings.TrimSpace(shiftHeadings(d.InstructionsMarkdown, 3)))
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+func writeAction(b *strings.Builder, a app.ActionDescriptor, opts Options) error {
+	name := a.DisplayName
+	if name == "" {
+		name = a.Name
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", name)
+	fmt.Fprintf(b, "- Name: %s\n", codeSpan(a.Name))
+	if a.Description != "" {
+		fmt.Fprintf(b, "\n%s\n", a.Description)
+	}
+
+	if a.InputSchema != nil {
+		b.WriteString("\n#### Input\n\n")
+		if err := writeSchema(b, a.InputSchema, opts.Examples); err != nil {
+			return fmt.Errorf("input: %w", err)
+		}
+	}
+	if a.OutputSchema != nil {
+		b.WriteString("\n#### Output\n\n")
+		if err := writeSchema(b, a.OutputSchema, false); err != nil {
+			return fmt.Errorf("output: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeSchema writes the table of the properties of the schema, followed by an example when asked for.
+func writeSchema(b *strings.Builder, s *app.JSONSchema, example bool) error {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return fmt.Errorf("decode schema: %w", err)
+	}
+
+	names := s.PropertyOrder()
+	if len(names) == 0 {
+		b.WriteString("None.\n")
+		return nil
+	}
+
+	properties, _ := raw["properties"].(map[string]any)
+	required := make(map[string]bool)
+	if list, ok := raw["required"].([]any); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	b.WriteString("| Name | Type | Required | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, name := range names {
+		p, _ := properties[name].(map[string]any)
+
+		req := "no"
+		if required[name] {
+			req = "yes"
+		}
+
+		def := ""
+		if v, ok := p["default"]; ok {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("property %s: default: %w", name, err)
+			}
+			def = codeSpan(string(data))
+		}
+
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			cell(codeSpan(name)),
+			cell(typeName(p)),
+			req,
+			cell(def),
+			cell(describeProperty(p, s.Annotations(name))),
+		)
+	}
+
+	if example {
+		ex, err := s.MaximalExample()
+		if err != nil {
+			return fmt.Errorf("example: %w", err)
+		}
+		data, err := json.MarshalIndent(ex, "", "  ")
+		if err != nil {
+			return fmt.Errorf("example: %w", err)
+		}
+		fmt.Fprintf(b, "\nExample:\n\n```json\n%s\n```\n", data)
+	}
+
+	return nil
+}
+
+// typeName returns the JSON schema type of the property, with its format and the type of its items.
+func typeName(p map[string]any) string {
+	var types []string
+	switch t := p["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	if len(types) == 0 {
+		return "any"
+	}
+
+	format, _ := p["format"].(string)
+	for i, t := range types {
+		switch {
+		case t == "array":
+			if items, ok := p["items"].(map[string]any); ok {
+				types[i] = "array of " + typeName(items)
+			}
+		case t != "null" && format != "":
+			types[i] = fmt.Sprintf("%s (%s)", t, format)
+		}
+	}
+
+	return strings.Join(types, " or ")
+}
+
+// describeProperty returns the description of the property, followed by its constraints and annotations.
+func describeProperty(p map[string]any, annotations app.Annotations) string {
+	var sentences []string
+	description, _ := p["description"].(string)
+	if description == "" {
+		description, _ = p["title"].(string)
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		if !strings.ContainsAny(description[len(description)-1:], ".!?") {
+			description += "."
+		}
+		sentences = append(sentences, description)
+	}
+
+	if values, ok := p["enum"].([]any); ok {
+		sentences = append(sentences, "One of "+codeList(values)+".")
+	}
+	if v, ok := p["const"]; ok {
+		sentences = append(sentences, "Always "+codeList([]any{v})+".")
+	}
+
+	constraints := []struct {
+		keyword string
+		label   string
+	}{
+		{"minimum", "Minimum"},
+		{"exclusiveMinimum", "Exclusive minimum"},
+		{"maximum", "Maximum"},
+		{"exclusiveMaximum", "Exclusive maximum"},
+		{"multipleOf", "Multiple of"},
+		{"minLength", "Minimum length"},
+		{"maxLength", "Maximum length"},
+		{"minItems", "Minimum items"},
+		{"maxItems", "Maximum items"},
+	}
+	for _, c := range constraints {
+		if v, ok := p[c.keyword].(json.Number); ok {
+			sentences = append(sentences, fmt.Sprintf("%s: %s.", c.label, v))
+		}
+	}
+	if pattern, ok := p["pattern"].(string); ok {
+		sentences = append(sentences, "Pattern: "+codeSpan(pattern)+".")
+	}
+	if unique, _ := p["uniqueItems"].(bool); unique {
+		sentences = append(sentences, "Unique items.")
+	}
+	if items, ok := p["items"].(map[string]any); ok {
+		if values, ok := items["enum"].([]any); ok {
+			sentences = append(sentences, "Items are one of "+codeList(values)+".")
+		}
+	}
+
+	if annotations.Immutable {
+		sentences = append(sentences, "Immutable.")
+	}
+	if annotations.Secret {
+		sentences = append(sentences, "Secret.")
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// codeList returns the JSON values as a comma separated list of code spans.
+func codeList(values []any) string {
+	spans := make([]string, 0, len(values))
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		}
+		spans = append(spans, codeSpan(string(data)))
+	}
+
+	return strings.Join(spans, ", ")
+}
+
+// operations returns the names of the operations supported by the resource type.
+func operations(d *app.ResourceDescriptor) []string {
+	supported := []struct {
+		name string
+		ok   bool
+	}{
+		{"create", d.CreateSupported},
+		{"read", d.ReadSupported},
+		{"update", d.UpdateSupported},
+		{"delete", d.DeleteSupported},
+		{"list", d.ListSupported},
+		{"health check", d.HealthCheckSupported},
+	}
+
+	var ops []string
+	for _, s := range supported {
+		if s.ok {
+			ops = append(ops, s.name)
+		}
+	}
+	if len(ops) == 0 {
+		ops = append(ops, "none")
+	}
+
+	return ops
+}
+
+// lifecycleStage returns the name of the lifecycle stage, or an empty string when it is unspecified.
+func lifecycleStage(s app.LifecycleStage) string {
+	if s == 0 {
+		return ""
+	}
+
+	return s.String()
+}
+
+// codeSpan returns s as a Markdown code span, delimited by more backticks than s contains in a row.
+func codeSpan(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	fence := strings.Repeat("`", longest+1)
+	if longest > 0 {
+		return fence + " " + s + " " + fence
+	}
+
+	return fence + s + fence
+}
+
+// cell escapes the text so that it fits in a cell of a Markdown table.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func escapeLinkText(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
+
+// shiftHeadings increases the level of the headings of the Markdown, outside of fenced code blocks,
+// so that the highest level heading has the given level. Levels are capped at 6.
+func shiftHeadings(md string, level int) string {
+	lines := strings.Split(md, "\n")
+
+	highest := 0
+	forEachHeading(lines, func(_, l int) {
+		if highest == 0 || l < highest {
+			highest = l
+		}
+	})
+	if highest == 0 || highest >= level {
+		return md
+	}
+
+	shift := level - highest
+	forEachHeading(lines, func(i, l int) {
+		lines[i] = strings.Repeat("#", min(l+shift, 6)) + lines[i][l:]
+	})
+
+	return strings.Join(lines, "\n")
+}
+
+// forEachHeading calls fn with the index and the level of the ATX headings of the lines,
+// skipping fenced code blocks.
+func forEachHeading(lines []string, fn func(i, level int)) {
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := codeFence(line); f != "" {
+			fence = f
+			continue
+		}
+		if l := headingLevel(line); l > 0 {
+			fn(i, l)
+		}
+	}
+}
+
+// codeFence returns the fence that opens a fenced code block on the line, or an empty string.
+func codeFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, c := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if n >= 3 {
+			return strings.Repeat(c, n)
+		}
+	}
+
+	return ""
+}
+
+// headingLevel returns the level of the ATX heading on the line, which must start with #, or 0.
+func headingLevel(line string) int {
+	n := len(line) - len(strings.TrimLeft(line, "#"))
+	if n == 0 || n > 6 {
+		return 0
+	}
+	if len(line) > n && line[n] != ' ' && line[n] != '\t' {
+		return 0
+	}
+
+	return n
+}
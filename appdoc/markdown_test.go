@@ -0,0 +1,108 @@
+package appdoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+func TestWriteSchema(t *testing.T) {
+	schema := app.MustParseJSONSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"size": {
+				"type": "string",
+				"enum": ["small", "large"],
+				"default": "small",
+				"description": "The size\nof the cluster.",
+				"x-tempest-order": 1
+			},
+			"nodes": {
+				"type": "integer",
+				"minimum": 1,
+				"maximum": 10,
+				"title": "Nodes",
+				"x-tempest-order": 2
+			},
+			"password": {
+				"type": "string",
+				"minLength": 8,
+				"pattern": "^[a-z|]+$",
+				"x-tempest-secret": true
+			},
+			"region": {
+				"type": ["string", "null"],
+				"format": "hostname",
+				"x-tempest-immutable": true
+			},
+			"zones": {
+				"type": "array",
+				"items": {"type": "string", "enum": ["a", "b"]},
+				"maxItems": 2
+			},
+			"metadata": {}
+		},
+		"required": ["size"]
+	}`))
+
+	var b strings.Builder
+	require.NoError(t, writeSchema(&b, schema, false))
+	assert.Equal(t, "| Name | Type | Required | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `size` | string | yes | `\"small\"` | The size of the cluster. One of `\"small\"`, `\"large\"`. |\n"+
+		"| `nodes` | integer | no |  | Nodes. Minimum: 1. Maximum: 10. |\n"+
+		"| `metadata` | any | no |  |  |\n"+
+		"| `password` | string | no |  | Minimum length: 8. Pattern: `^[a-z\\|]+$`. Secret. |\n"+
+		"| `region` | string (hostname) or null | no |  | Immutable. |\n"+
+		"| `zones` | array of string | no |  | Maximum items: 2. Items are one of `\"a\"`, `\"b\"`. |\n",
+		b.String())
+}
+
+func TestCodeSpan(t *testing.T) {
+	testCases := map[string]string{
+		"name":   "`name`",
+		"a`b":    "`` a`b ``",
+		"``x``":  "``` ``x`` ```",
+		`"json"`: "`\"json\"`",
+	}
+	for in, want := range testCases {
+		assert.Equal(t, want, codeSpan(in), in)
+	}
+}
+
+func TestShiftHeadings(t *testing.T) {
+	testCases := []struct {
+		desc string
+		md   string
+		want string
+	}{
+		{
+			desc: "OK - Shifted",
+			md:   "# Title\n\n## Section\n\ntext",
+			want: "### Title\n\n#### Section\n\ntext",
+		},
+		{
+			desc: "OK - Capped",
+			md:   "# Title\n\n###### Deep",
+			want: "### Title\n\n###### Deep",
+		},
+		{
+			desc: "OK - Code Blocks Ignored",
+			md:   "## Run\n\n```sh\n# a comment\n```\n\n~~~\n# another\n~~~",
+			want: "### Run\n\n```sh\n# a comment\n```\n\n~~~\n# another\n~~~",
+		},
+		{
+			desc: "OK - Not Headings",
+			md:   "#hashtag\n####### seven",
+			want: "#hashtag\n####### seven",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, shiftHeadings(tc.md, 3))
+		})
+	}
+}
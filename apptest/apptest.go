@@ -0,0 +1,244 @@
+// Package apptest provides utilities for testing Tempest apps built with the app package.
+//
+// A Harness calls the RPCs of an *app.App with the SDK's Go types, instead of hand-built protobuf
+// requests, and fills in the Metadata and Environment that Tempest sends with every request:
+//
+//	h := apptest.New(t, a)
+//	res, err := h.Create("bucket", map[string]any{"name": "logs"})
+//	require.NoError(t, err)
+//
+// NewServer runs the same calls over a real Connect server, which catches values that don't
+// survive the serialization to and from protobuf. Run executes a test against both.
+//
+// AssertDescribeGolden compares the Describe output with a golden file, and a Recorder replays the
+// interactions of handlers with the services they call. apptest does not define -update or -record
+// flags, as flags registered by a library conflict with the flags of the test packages that import it.
+// Set environment variables instead:
+//
+//	APPTEST_UPDATE=1 go test ./...  # write the golden files, see UpdateEnv
+//	APPTEST_RECORD=1 go test ./...  # record the cassettes against the live services, see RecordEnv
+package apptest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// Harness calls the RPCs of an App. Errors returned by its methods are *connect.Error values,
+// so the code can be checked with connect.CodeOf.
+type Harness struct {
+	// Metadata is sent with every operation, list and action request.
+	Metadata *app.Metadata
+	// Environment is sent with every operation and action request. It starts with the DefaultEnvironment.
+	Environment []app.EnvironmentVariable
+
+	tb     testing.TB
+	client appv1connect.AppServiceClient
+	typed  *app.Client
+}
+
+// Option configures a Harness.
+type Option func(*Harness)
+
+// WithMetadata replaces the default Metadata sent with requests.
+func WithMetadata(m *app.Metadata) Option {
+	return func(h *Harness) {
+		h.Metadata = m
+	}
+}
+
+// WithEnvironment adds environment variables to the requests. They replace the variables of the
+// DefaultEnvironment with the same key.
+func WithEnvironment(vars ...app.EnvironmentVariable) Option {
+	return func(h *Harness) {
+		h.Environment = append(h.Environment, vars...)
+	}
+}
+
+// Variable returns a plain environment variable.
+func Variable(key, value string) app.EnvironmentVariable {
+	return app.EnvironmentVariable{Key: key, Value: value, Type: app.ENVIRONMENT_VARIABLE_TYPE_VAR}
+}
+
+// Secret returns a secret environment variable. Its value is redacted from validation errors.
+func Secret(key, value string) app.EnvironmentVariable {
+	return app.EnvironmentVariable{Key: key, Value: value, Type: app.ENVIRONMENT_VARIABLE_TYPE_SECRET}
+}
+
+// DefaultMetadata returns the Metadata used by a Harness unless WithMetadata is set:
+// a Project owned by a user and a team, authored by the user.
+func DefaultMetadata() *app.Metadata {
+	author := app.Owner{
+		Email: "jane.doe@example.com",
+		Name:  "Jane Doe",
+		Type:  app.OwnerTypeUser,
+	}
+
+	return &app.Metadata{
+		ProjectID:   "prj_01J9ZQ4T3W8X5Y6Z7A8B9C0D1E",
+		ProjectName: "apptest-project",
+		Owners: []app.Owner{
+			author,
+			{
+				Email: "platform@example.com",
+				Name:  "Platform",
+				Type:  app.OwnerTypeTeam,
+			},
+		},
+		Author: author,
+	}
+}
+
+// DefaultEnvironment returns the environment variables that a Harness sends before the ones added
+// with WithEnvironment: plain variables for the region and the log level, and an API token secret.
+func DefaultEnvironment() []app.EnvironmentVariable {
+	return []app.EnvironmentVariable{
+		Variable("REGION", "us-east-1"),
+		Variable("LOG_LEVEL", "info"),
+		Secret("API_TOKEN", "apptest-api-token-6f1c2d9e"),
+	}
+}
+
+// New returns a Harness that calls the App in-process.
+func New(tb testing.TB, a *app.App, opts ...Option) *Harness {
+	return newHarness(tb, a, opts)
+}
+
+// NewServer returns a Harness that calls the App through a Connect client and an httptest server,
+// so that every request and response is serialized as it would be when deployed.
+// The server is closed when the test finishes.
+func NewServer(tb testing.TB, a *app.App, opts ...Option) *Harness {
+	mux := http.NewServeMux()
+	mux.Handle(appv1connect.NewAppServiceHandler(a))
+
+	srv := httptest.NewServer(mux)
+	tb.Cleanup(srv.Close)
+
+	return newHarness(tb, appv1connect.NewAppServiceClient(srv.Client(), srv.URL), opts)
+}
+
+func newHarness(tb testing.TB, client appv1connect.AppServiceClient, opts []Option) *Harness {
+	h := &Harness{
+		Metadata:    DefaultMetadata(),
+		Environment: DefaultEnvironment(),
+		tb:          tb,
+		client:      client,
+		typed:       app.NewServiceClient(client),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Run runs fn as two subtests, "in-process" with New and "http" with NewServer.
+func Run(t *testing.T, a *app.App, fn func(t *testing.T, h *Harness), opts ...Option) {
+	t.Run("in-process", func(t *testing.T) {
+		fn(t, New(t, a, opts...))
+	})
+
+	t.Run("http", func(t *testing.T) {
+		fn(t, NewServer(t, a, opts...))
+	})
+}
+
+// Describe returns the definitions of the App.
+func (h *Harness) Describe() (*appv1.DescribeResponse, error) {
+	res, err := h.client.Describe(h.tb.Context(), connect.NewRequest(&appv1.DescribeRequest{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Msg, nil
+}
+
+// Create creates a resource of the type with the input.
+func (h *Harness) Create(resourceType string, input map[string]any) (*app.Resource, error) {
+	return h.typed.Create(h.tb.Context(), h.operationRequest(&app.Resource{Type: resourceType}, input))
+}
+
+// Read reads the current state of the resource.
+func (h *Harness) Read(r *app.Resource) (*app.Resource, error) {
+	return h.typed.Read(h.tb.Context(), h.operationRequest(r, nil))
+}
+
+// Update updates the resource with the input.
+func (h *Harness) Update(r *app.Resource, input map[string]any) (*app.Resource, error) {
+	return h.typed.Update(h.tb.Context(), h.operationRequest(r, input))
+}
+
+// Delete deletes the resource.
+func (h *Harness) Delete(r *app.Resource) (*app.Resource, error) {
+	return h.typed.Delete(h.tb.Context(), h.operationRequest(r, nil))
+}
+
+func (h *Harness) operationRequest(r *app.Resource, input map[string]any) *app.OperationRequest {
+	return &app.OperationRequest{
+		Metadata:    h.Metadata,
+		Resource:    r,
+		Input:       input,
+		Environment: h.environment(),
+	}
+}
+
+// ListPage lists a single page of resources of the type, starting at the next token.
+// It returns the token of the following page, which is empty on the last page.
+func (h *Harness) ListPage(resourceType, next string) ([]*app.Resource, string, error) {
+	res, err := h.typed.ListPage(h.tb.Context(), &app.ListRequest{
+		Metadata: h.Metadata,
+		Resource: &app.Resource{Type: resourceType},
+		Next:     next,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return res.Resources, res.Next, nil
+}
+
+// List lists every resource of the type, following the pages until the last one.
+func (h *Harness) List(resourceType string) ([]*app.Resource, error) {
+	return h.typed.List(h.tb.Context(), &app.ListRequest{
+		Metadata: h.Metadata,
+		Resource: &app.Resource{Type: resourceType},
+	})
+}
+
+// Action executes the named action on the resource and returns its output.
+func (h *Harness) Action(r *app.Resource, name string, input map[string]any) (map[string]any, error) {
+	res, err := h.typed.ExecuteAction(h.tb.Context(), &app.ActionRequest{
+		Metadata:    h.Metadata,
+		Resource:    r,
+		Action:      name,
+		Input:       input,
+		Environment: h.environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Output, nil
+}
+
+// Health returns the health of the resource type.
+func (h *Harness) Health(resourceType string) (*app.HealthCheckResponse, error) {
+	return h.typed.HealthCheck(h.tb.Context(), resourceType)
+}
+
+// environment returns the Environment keyed by the variable keys. Later variables replace earlier ones.
+func (h *Harness) environment() map[string]app.EnvironmentVariable {
+	env := make(map[string]app.EnvironmentVariable, len(h.Environment))
+	for _, v := range h.Environment {
+		env[v.Key] = v
+	}
+
+	return env
+}
@@ -0,0 +1,281 @@
+package apptest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+const bucketSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"size": {"type": "integer", "default": 1}
+	},
+	"required": ["name"],
+	"additionalProperties": false
+}`
+
+// bucketStore is a minimal backend for the tests of the harness.
+type bucketStore struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]any
+	nextID  int
+
+	// requests records the OperationRequests received by the handlers.
+	requests []*app.OperationRequest
+}
+
+func (s *bucketStore) resource(id string) *app.Resource {
+	return &app.Resource{
+		ExternalID:  id,
+		DisplayName: s.buckets[id]["name"].(string),
+		Properties:  maps.Clone(s.buckets[id]),
+	}
+}
+
+func (s *bucketStore) create(_ context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	s.nextID++
+	id := "bucket-" + strconv.Itoa(s.nextID)
+	s.buckets[id] = req.Input
+
+	return &app.OperationResponse{Resource: s.resource(id)}, nil
+}
+
+func (s *bucketStore) read(_ context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[req.Resource.ExternalID]; !ok {
+		return nil, fmt.Errorf("bucket %s: %w", req.Resource.ExternalID, app.ErrNotFound)
+	}
+
+	return &app.OperationResponse{Resource: s.resource(req.Resource.ExternalID)}, nil
+}
+
+func (s *bucketStore) update(_ context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maps.Copy(s.buckets[req.Resource.ExternalID], req.Input)
+
+	return &app.OperationResponse{Resource: s.resource(req.Resource.ExternalID)}, nil
+}
+
+func (s *bucketStore) delete(_ context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.resource(req.Resource.ExternalID)
+	delete(s.buckets, req.Resource.ExternalID)
+
+	return &app.OperationResponse{Resource: r}, nil
+}
+
+// list returns one bucket per page.
+func (s *bucketStore) list(_ context.Context, req *app.ListRequest) (*app.ListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := slices.Sorted(maps.Keys(s.buckets))
+	i := 0
+	if req.Next != "" {
+		i = slices.Index(ids, req.Next)
+	}
+	if i < 0 || i >= len(ids) {
+		return &app.ListResponse{}, nil
+	}
+
+	res := &app.ListResponse{Resources: []*app.Resource{s.resource(ids[i])}}
+	if i+1 < len(ids) {
+		res.Next = ids[i+1]
+	}
+
+	return res, nil
+}
+
+func newBucketApp(t testing.TB) (*app.App, *bucketStore) {
+	t.Helper()
+
+	s := &bucketStore{buckets: make(map[string]map[string]any)}
+	properties := app.MustParseJSONSchema([]byte(bucketSchema))
+
+	rd, err := app.NewResource("bucket").
+		DisplayName("Bucket").
+		Properties(properties).
+		Create(s.create, properties).
+		Update(s.update, properties).
+		Read(s.read).
+		Delete(s.delete).
+		List(s.list).
+		HealthCheck(func(context.Context) (*app.HealthCheckResponse, error) {
+			return &app.HealthCheckResponse{Status: app.HealthCheckStatusDegraded, Message: "slow"}, nil
+		}).
+		Action(app.ActionDefinition{
+			Name: "empty",
+			Handler: func(_ context.Context, req *app.ActionRequest) (*app.ActionResponse, error) {
+				return &app.ActionResponse{Output: map[string]any{
+					"emptied": req.Resource.ExternalID,
+					"token":   req.Environment["TOKEN"].Value,
+				}}, nil
+			},
+		}).
+		Build()
+	require.NoError(t, err)
+
+	return app.New(app.WithResourceDefinition(rd)), s
+}
+
+func TestHarness(t *testing.T) {
+	a, store := newBucketApp(t)
+
+	Run(t, a, func(t *testing.T, h *Harness) {
+		created, err := h.Create("bucket", map[string]any{"name": "logs"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ExternalID)
+		assert.Equal(t, "bucket", created.Type)
+		assert.Equal(t, map[string]any{"name": "logs", "size": float64(1)}, created.Properties)
+
+		req := store.requests[len(store.requests)-1]
+		assert.Equal(t, DefaultMetadata(), req.Metadata)
+		assert.Equal(t, Secret("TOKEN", "s3cr3t"), req.Environment["TOKEN"])
+		for _, v := range DefaultEnvironment() {
+			assert.Equal(t, v, req.Environment[v.Key])
+		}
+
+		read, err := h.Read(created)
+		require.NoError(t, err)
+		assert.Equal(t, created, read)
+
+		updated, err := h.Update(created, map[string]any{"name": "logs", "size": 5})
+		require.NoError(t, err)
+		assert.Equal(t, float64(5), updated.Properties["size"])
+
+		_, err = h.Create("bucket", map[string]any{"name": "assets"})
+		require.NoError(t, err)
+
+		listed, err := h.List("bucket")
+		require.NoError(t, err)
+		assert.Len(t, listed, len(store.buckets))
+		assert.Contains(t, listed, updated)
+
+		out, err := h.Action(created, "empty", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"emptied": created.ExternalID, "token": "s3cr3t"}, out)
+
+		health, err := h.Health("bucket")
+		require.NoError(t, err)
+		assert.Equal(t, &app.HealthCheckResponse{Status: app.HealthCheckStatusDegraded, Message: "slow"}, health)
+
+		_, err = h.Delete(created)
+		require.NoError(t, err)
+
+		_, err = h.Read(created)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+		describe, err := h.Describe()
+		require.NoError(t, err)
+		require.Len(t, describe.ResourceDefinitions, 1)
+		assert.Equal(t, "bucket", describe.ResourceDefinitions[0].Type)
+	}, WithEnvironment(Secret("TOKEN", "s3cr3t")))
+}
+
+func TestHarnessEnvironment(t *testing.T) {
+	a, store := newBucketApp(t)
+
+	h := New(t, a, WithEnvironment(Variable("REGION", "eu-west-1")))
+	_, err := h.Create("bucket", map[string]any{"name": "logs"})
+	require.NoError(t, err)
+
+	env := store.requests[len(store.requests)-1].Environment
+	assert.Equal(t, Variable("REGION", "eu-west-1"), env["REGION"])
+	assert.Equal(t, Variable("LOG_LEVEL", "info"), env["LOG_LEVEL"])
+	assert.Equal(t, app.ENVIRONMENT_VARIABLE_TYPE_SECRET, env["API_TOKEN"].Type)
+	assert.NotEmpty(t, env["API_TOKEN"].Value)
+}
+
+func TestHarnessErrors(t *testing.T) {
+	a, _ := newBucketApp(t)
+
+	testCases := []struct {
+		desc string
+		call func(h *Harness) error
+		code connect.Code
+	}{
+		{
+			desc: "ERR - Unknown Resource Type",
+			call: func(h *Harness) error {
+				_, err := h.Create("unknown", nil)
+				return err
+			},
+			code: connect.CodeNotFound,
+		},
+		{
+			desc: "ERR - Invalid Input",
+			call: func(h *Harness) error {
+				_, err := h.Create("bucket", map[string]any{"size": 2})
+				return err
+			},
+			code: connect.CodeInvalidArgument,
+		},
+		{
+			desc: "ERR - Missing External ID",
+			call: func(h *Harness) error {
+				_, err := h.Read(&app.Resource{Type: "bucket"})
+				return err
+			},
+			code: connect.CodeInvalidArgument,
+		},
+		{
+			desc: "ERR - Unknown Action",
+			call: func(h *Harness) error {
+				_, err := h.Action(&app.Resource{Type: "bucket", ExternalID: "bucket-1"}, "unknown", nil)
+				return err
+			},
+			code: connect.CodeNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			Run(t, a, func(t *testing.T, h *Harness) {
+				err := tc.call(h)
+				require.Error(t, err)
+
+				var connectErr *connect.Error
+				assert.True(t, errors.As(err, &connectErr))
+				assert.Equal(t, tc.code, connect.CodeOf(err))
+			})
+		})
+	}
+}
+
+func TestHarnessListLoop(t *testing.T) {
+	properties := app.MustParseJSONSchema(app.GenericEmptySchema)
+	rd, err := app.NewResource("loop").
+		Properties(properties).
+		List(func(context.Context, *app.ListRequest) (*app.ListResponse, error) {
+			return &app.ListResponse{Next: "same"}, nil
+		}).
+		Build()
+	require.NoError(t, err)
+
+	h := New(t, app.New(app.WithResourceDefinition(rd)))
+
+	_, err = h.List("loop")
+	assert.EqualError(t, err, `list returned the next token "same" more than once`)
+}
@@ -0,0 +1,245 @@
+package apptest
+
+import (
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/sdk-go/app"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ConformanceOptions configures RunConformance.
+type ConformanceOptions struct {
+	// ResourceType is the type of the ResourceDefinition to test.
+	// It can be omitted when the App has a single ResourceDefinition.
+	ResourceType string
+	// CreateInput is the input of the Create operation.
+	// Defaults to the minimal example of the create input schema.
+	CreateInput map[string]any
+	// UpdateInput is the input of the Update operation. The properties of the input that are declared
+	// in the PropertiesSchema must be reflected by the next Read.
+	// Defaults to the minimal example of the update input schema.
+	UpdateInput map[string]any
+	// HarnessOptions configure the Harness used to call the App, for example its Environment.
+	HarnessOptions []Option
+	// SchemaOptions are used to compile the described PropertiesSchema, when it uses custom formats or keywords.
+	SchemaOptions []app.SchemaOption
+}
+
+// RunConformance checks that a ResourceDefinition of the App behaves as Tempest expects,
+// both in-process and over HTTP.
+//
+// It checks that the operations flagged as supported by Describe are registered and the others are not,
+// then runs the lifecycle of a resource with the operations the ResourceDefinition supports:
+// create, read, list contains it, update, read reflects the update, delete and read fails with
+// connect.CodeNotFound. Read handlers must return app.ErrNotFound for deleted resources.
+// The ExternalID must stay the same across the lifecycle, and the properties of every returned
+// resource must validate against the PropertiesSchema.
+//
+// The resources are created in the external system of the handlers, so RunConformance should
+// run against a test account or a fake backend. A resource is deleted when the test finishes if a
+// step fails before the lifecycle deletes it.
+func RunConformance(t *testing.T, a *app.App, opts ConformanceOptions) {
+	t.Helper()
+
+	Run(t, a, func(t *testing.T, h *Harness) {
+		c := newConformance(t, h, opts)
+
+		t.Run("describe", c.checkDescribe)
+		t.Run("lifecycle", func(t *testing.T) { c.checkLifecycle(t) })
+	}, opts.HarnessOptions...)
+}
+
+type conformance struct {
+	h    *Harness
+	opts ConformanceOptions
+	rd   *appv1.ResourceDefinition
+
+	properties *app.JSONSchema
+}
+
+func newConformance(t *testing.T, h *Harness, opts ConformanceOptions) *conformance {
+	t.Helper()
+
+	describe, err := h.Describe()
+	require.NoError(t, err, "describe")
+
+	c := &conformance{h: h, opts: opts}
+
+	for _, rd := range describe.ResourceDefinitions {
+		if rd.Type == opts.ResourceType || (opts.ResourceType == "" && len(describe.ResourceDefinitions) == 1) {
+			c.rd = rd
+		}
+	}
+	require.NotNil(t, c.rd, "resource type %q not described", opts.ResourceType)
+
+	c.properties = c.schema(t, c.rd.PropertiesSchema)
+
+	return c
+}
+
+// schema compiles a schema from the DescribeResponse.
+func (c *conformance) schema(t testing.TB, s *structpb.Struct) *app.JSONSchema {
+	t.Helper()
+
+	compiled, err := compileSchema(s, c.opts.SchemaOptions)
+	require.NoError(t, err, "compile described schema")
+
+	return compiled
+}
+
+// checkDescribe calls each operation that Describe flags as unsupported, and expects it to be rejected.
+// The supported operations are called by checkLifecycle.
+func (c *conformance) checkDescribe(t *testing.T) {
+	unsupported := &app.Resource{Type: c.rd.Type, ExternalID: "apptest-unsupported"}
+
+	for _, op := range []struct {
+		name      string
+		supported bool
+		call      func() error
+	}{
+		{"create", c.rd.CreateSupported, func() error { _, err := c.h.Create(c.rd.Type, nil); return err }},
+		{"read", c.rd.ReadSupported, func() error { _, err := c.h.Read(unsupported); return err }},
+		{"update", c.rd.UpdateSupported, func() error { _, err := c.h.Update(unsupported, nil); return err }},
+		{"delete", c.rd.DeleteSupported, func() error { _, err := c.h.Delete(unsupported); return err }},
+		{"list", c.rd.ListSupported, func() error { _, _, err := c.h.ListPage(c.rd.Type, ""); return err }},
+		{"health check", c.rd.HealthcheckSupported, func() error { _, err := c.h.Health(c.rd.Type); return err }},
+	} {
+		if op.supported {
+			continue
+		}
+
+		err := op.call()
+		if assert.Error(t, err, "%s is not flagged as supported, but succeeded", op.name) {
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), "%s is not flagged as supported: %v", op.name, err)
+			assert.ErrorContains(t, err, "not supported", "%s is not flagged as supported", op.name)
+		}
+	}
+
+	if c.rd.HealthcheckSupported {
+		res, err := c.h.Health(c.rd.Type)
+		if assert.NoError(t, err, "health check") {
+			assert.NotEqual(t, app.HealthCheckStatusUnknown, res.Status, "health check status")
+		}
+	}
+}
+
+// checkLifecycle runs the lifecycle of a resource. If a step fails before the resource is deleted,
+// the resource is deleted when the test finishes, so that failed runs don't leak resources.
+func (c *conformance) checkLifecycle(t testing.TB) {
+	if !c.rd.CreateSupported {
+		t.Skipf("resource type %s does not support create", c.rd.Type)
+	}
+
+	createInput := c.input(t, c.opts.CreateInput, c.rd.CreateInputSchema)
+
+	created, err := c.h.Create(c.rd.Type, createInput)
+	require.NoError(t, err, "create")
+	require.NotEmpty(t, created.ExternalID, "create must return the ExternalID of the resource")
+	c.validate(t, "create", created)
+
+	id := created.ExternalID
+
+	var deleted bool
+	if c.rd.DeleteSupported {
+		t.Cleanup(func() {
+			if deleted {
+				return
+			}
+			if _, err := c.h.Delete(created); err != nil {
+				t.Errorf("delete %s after the lifecycle failed: %v", id, err)
+			}
+		})
+	}
+
+	if c.rd.ReadSupported {
+		read, err := c.h.Read(created)
+		require.NoError(t, err, "read after create")
+		assert.Equal(t, id, read.ExternalID, "read after create returned a different ExternalID")
+		c.validate(t, "read after create", read)
+	}
+
+	if c.rd.ListSupported {
+		listed, err := c.h.List(c.rd.Type)
+		require.NoError(t, err, "list")
+
+		i := slices.IndexFunc(listed, func(r *app.Resource) bool { return r.ExternalID == id })
+		assert.NotEqual(t, -1, i, "list does not contain the created resource %s", id)
+		for _, r := range listed {
+			c.validate(t, "list", r)
+		}
+	}
+
+	if c.rd.UpdateSupported {
+		updateInput := c.input(t, c.opts.UpdateInput, c.rd.UpdateInputSchema)
+
+		updated, err := c.h.Update(created, updateInput)
+		require.NoError(t, err, "update")
+		assert.Equal(t, id, updated.ExternalID, "update returned a different ExternalID")
+		c.validate(t, "update", updated)
+
+		if c.rd.ReadSupported {
+			read, err := c.h.Read(updated)
+			require.NoError(t, err, "read after update")
+			assert.Equal(t, id, read.ExternalID, "read after update returned a different ExternalID")
+			c.validate(t, "read after update", read)
+			c.checkReflected(t, updateInput, read)
+		}
+	}
+
+	if c.rd.DeleteSupported {
+		_, err := c.h.Delete(created)
+		require.NoError(t, err, "delete")
+		deleted = true
+
+		if c.rd.ReadSupported {
+			_, err := c.h.Read(created)
+			require.Error(t, err, "read after delete must fail")
+			assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err), "read after delete must wrap app.ErrNotFound: %v", err)
+		}
+	}
+}
+
+// input returns the configured input, or the minimal example of the input schema.
+func (c *conformance) input(t testing.TB, input map[string]any, s *structpb.Struct) map[string]any {
+	t.Helper()
+
+	if input != nil {
+		return input
+	}
+
+	example, err := c.schema(t, s).MinimalExample()
+	require.NoError(t, err, "generate example input")
+
+	return example
+}
+
+func (c *conformance) validate(t testing.TB, step string, r *app.Resource) {
+	t.Helper()
+
+	assert.Equal(t, c.rd.Type, r.Type, "%s returned a resource of another type", step)
+	assert.NoError(t, c.properties.Validate(r.Properties), "%s returned properties that don't validate against the properties schema", step)
+}
+
+// checkReflected checks that the properties of the update input declared in the PropertiesSchema
+// have the value of the input in the resource.
+func (c *conformance) checkReflected(t testing.TB, input map[string]any, r *app.Resource) {
+	t.Helper()
+
+	// Round trip the input through protobuf, so that the numbers compare as float64.
+	s, err := structpb.NewStruct(input)
+	require.NoError(t, err)
+
+	declared := c.rd.PropertiesSchema.GetFields()["properties"].GetStructValue().GetFields()
+	for k, v := range s.AsMap() {
+		if _, ok := declared[k]; !ok {
+			continue
+		}
+
+		assert.Equal(t, v, r.Properties[k], "read after update does not reflect the updated property %q", k)
+	}
+}
@@ -0,0 +1,135 @@
+package apptest
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+func TestRunConformance(t *testing.T) {
+	t.Run("OK - Full Lifecycle", func(t *testing.T) {
+		a, _ := newBucketApp(t)
+
+		RunConformance(t, a, ConformanceOptions{
+			CreateInput: map[string]any{"name": "logs"},
+			UpdateInput: map[string]any{"name": "logs", "size": 10},
+		})
+	})
+
+	t.Run("OK - Example Inputs", func(t *testing.T) {
+		a, _ := newBucketApp(t)
+
+		RunConformance(t, a, ConformanceOptions{ResourceType: "bucket"})
+	})
+
+	t.Run("OK - Partial Lifecycle", func(t *testing.T) {
+		s := &bucketStore{buckets: make(map[string]map[string]any)}
+		properties := app.MustParseJSONSchema([]byte(bucketSchema))
+
+		rd, err := app.NewResource("bucket").
+			Properties(properties).
+			Create(s.create, properties).
+			Read(s.read).
+			Build()
+		require.NoError(t, err)
+
+		RunConformance(t, app.New(app.WithResourceDefinition(rd)), ConformanceOptions{
+			CreateInput: map[string]any{"name": "logs"},
+		})
+	})
+}
+
+// failureRecorder is a testing.TB that records failures instead of failing the test, and runs
+// the cleanups when run returns.
+type failureRecorder struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+}
+
+func (r *failureRecorder) Helper() {}
+
+func (r *failureRecorder) Errorf(string, ...any) {
+	r.failed = true
+}
+
+func (r *failureRecorder) FailNow() {
+	r.failed = true
+	runtime.Goexit()
+}
+
+func (r *failureRecorder) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *failureRecorder) run(fn func(testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestConformanceLifecycleCleanup(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		updateErr error
+		failed    bool
+	}{
+		{
+			desc: "OK - Deleted Once",
+		},
+		{
+			desc:      "ERR - Deleted After Failed Update",
+			updateErr: errors.New("update failed"),
+			failed:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &bucketStore{buckets: make(map[string]map[string]any)}
+			properties := app.MustParseJSONSchema([]byte(bucketSchema))
+
+			var deletes int
+			rd, err := app.NewResource("bucket").
+				Properties(properties).
+				Create(s.create, properties).
+				Read(s.read).
+				Update(func(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+					if tc.updateErr != nil {
+						return nil, tc.updateErr
+					}
+					return s.update(ctx, req)
+				}, properties).
+				Delete(func(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+					deletes++
+					return s.delete(ctx, req)
+				}).
+				Build()
+			require.NoError(t, err)
+
+			opts := ConformanceOptions{
+				CreateInput: map[string]any{"name": "logs"},
+				UpdateInput: map[string]any{"name": "logs", "size": 10},
+			}
+			c := newConformance(t, New(t, app.New(app.WithResourceDefinition(rd))), opts)
+
+			r := &failureRecorder{TB: t}
+			r.run(c.checkLifecycle)
+
+			assert.Equal(t, tc.failed, r.failed)
+			assert.Equal(t, 1, deletes)
+			assert.Empty(t, s.buckets, "the created bucket must be deleted")
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package apptest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+	"github.com/tempestdx/sdk-go/app"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FuzzOptions configures Fuzz.
+type FuzzOptions struct {
+	// Resources are the resources on which the Update operation and the actions are called, keyed by type.
+	// Defaults to a resource with the ExternalID "apptest-fuzz".
+	Resources map[string]*app.Resource
+	// HTTP calls the App through a Connect server, as NewServer does, instead of in-process.
+	HTTP bool
+	// Seeds is the number of valid and invalid example inputs added to the seed corpus
+	// for each operation and action. Defaults to 4.
+	Seeds int
+	// HarnessOptions configure the Harness used to call the App, for example its Environment.
+	HarnessOptions []Option
+	// SchemaOptions are used to compile the described schemas, when they use custom formats or keywords.
+	SchemaOptions []app.SchemaOption
+}
+
+// Fuzz runs a native Go fuzz test against the inputs of the Create and Update operations and the
+// actions of every ResourceDefinition of the App:
+//
+//	func FuzzApp(f *testing.F) {
+//		apptest.Fuzz(f, newApp(), apptest.FuzzOptions{})
+//	}
+//
+// The inputs are generated from the input schemas, as random valid examples and as examples with a
+// violation that neither default values nor type coercion can repair, or decoded from the JSON bytes
+// produced by the fuzzing engine. Bytes that are not a JSON object are skipped. For every input, Fuzz checks that:
+//
+//   - no panic escapes the App
+//   - inputs with a violation fail with connect.CodeInvalidArgument, including the inputs decoded from
+//     the fuzzing engine that don't validate against the input schema once its default values are set
+//     and that type coercion can't repair
+//   - errors are *connect.Error values with a known code
+//   - successful responses validate against the PropertiesSchema or the action output schema
+//
+// The handlers are called with the inputs, so the App should be backed by fakes.
+// Without -fuzz, go test only runs the seed corpus.
+func Fuzz(f *testing.F, a *app.App, opts FuzzOptions) {
+	f.Helper()
+
+	var client appv1connect.AppServiceClient = a
+	if opts.HTTP {
+		mux := http.NewServeMux()
+		mux.Handle(appv1connect.NewAppServiceHandler(a))
+
+		srv := httptest.NewServer(mux)
+		f.Cleanup(srv.Close)
+
+		client = appv1connect.NewAppServiceClient(srv.Client(), srv.URL)
+	}
+
+	res, err := newHarness(f, client, opts.HarnessOptions).Describe()
+	if err != nil {
+		f.Fatalf("describe: %v", err)
+	}
+
+	targets, err := fuzzTargets(res, opts)
+	if err != nil {
+		f.Fatal(err)
+	}
+	if len(targets) == 0 {
+		f.Skip("the App has no operation or action with an input")
+	}
+
+	seeds := opts.Seeds
+	if seeds == 0 {
+		seeds = 4
+	}
+	for i := range targets {
+		for seed := range uint64(seeds) {
+			f.Add(uint16(i), fuzzValid, seed, []byte(nil))
+			f.Add(uint16(i), fuzzViolation, seed, []byte(nil))
+		}
+		f.Add(uint16(i), fuzzJSON, uint64(0), []byte(`{}`))
+	}
+
+	f.Fuzz(func(t *testing.T, target uint16, mode uint8, seed uint64, raw []byte) {
+		tg := targets[int(target)%len(targets)]
+
+		input, violated, ok := tg.input(mode, seed, raw)
+		if !ok {
+			return
+		}
+
+		h := newHarness(t, client, opts.HarnessOptions)
+		err := tg.call(t, h, input)
+		if err == nil {
+			return
+		}
+
+		var connectErr *connect.Error
+		if !errors.As(err, &connectErr) || connectErr.Code() == connect.CodeUnknown {
+			t.Fatalf("%s failed without a connect error code: %v", tg.name, err)
+		}
+
+		if violated && connectErr.Code() != connect.CodeInvalidArgument {
+			t.Fatalf("%s accepted an invalid input %v: %v", tg.name, input, err)
+		}
+	})
+}
+
+// The modes of generation of the fuzz inputs.
+const (
+	fuzzValid uint8 = iota
+	fuzzViolation
+	fuzzJSON
+	fuzzModes
+)
+
+// fuzzTarget is an operation or action with an input.
+type fuzzTarget struct {
+	name   string
+	input  func(mode uint8, seed uint64, raw []byte) (input map[string]any, violated, ok bool)
+	invoke func(h *Harness, input map[string]any) (output map[string]any, err error)
+	output *app.JSONSchema
+}
+
+// call invokes the target, turning panics into test failures, and validates the output of successful calls.
+func (tg *fuzzTarget) call(t *testing.T, h *Harness, input map[string]any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("%s panicked with input %v: %v\n%s", tg.name, input, r, debug.Stack())
+		}
+	}()
+
+	output, err := tg.invoke(h, input)
+	if err != nil {
+		return err
+	}
+
+	if err := tg.output.Validate(output); err != nil {
+		t.Fatalf("%s returned an output that does not validate: %v", tg.name, err)
+	}
+
+	return nil
+}
+
+func fuzzTargets(res *appv1.DescribeResponse, opts FuzzOptions) ([]*fuzzTarget, error) {
+	var targets []*fuzzTarget
+
+	for _, rd := range res.ResourceDefinitions {
+		properties, err := compileSchema(rd.PropertiesSchema, opts.SchemaOptions)
+		if err != nil {
+			return nil, fmt.Errorf("resource type %s properties schema: %w", rd.Type, err)
+		}
+
+		resource := opts.Resources[rd.Type]
+		if resource == nil {
+			resource = &app.Resource{Type: rd.Type, ExternalID: "apptest-fuzz"}
+		}
+
+		resourceProperties := func(r *app.Resource, err error) (map[string]any, error) {
+			if err != nil {
+				return nil, err
+			}
+			return r.Properties, nil
+		}
+
+		if rd.CreateSupported {
+			t, err := newFuzzTarget(rd.Type+" create", rd.CreateInputSchema, properties, nil, opts, func(h *Harness, input map[string]any) (map[string]any, error) {
+				return resourceProperties(h.Create(rd.Type, input))
+			})
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, t)
+		}
+
+		if rd.UpdateSupported {
+			// The App sets the immutable properties omitted from an update input to their stored value.
+			kept := immutableProperties(rd.PropertiesSchema, rd.UpdateInputSchema)
+			t, err := newFuzzTarget(rd.Type+" update", rd.UpdateInputSchema, properties, kept, opts, func(h *Harness, input map[string]any) (map[string]any, error) {
+				return resourceProperties(h.Update(resource, input))
+			})
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, t)
+		}
+
+		for _, ad := range rd.Actions {
+			output, err := compileSchema(ad.OutputSchema, opts.SchemaOptions)
+			if err != nil {
+				return nil, fmt.Errorf("resource type %s action %s output schema: %w", rd.Type, ad.Name, err)
+			}
+
+			t, err := newFuzzTarget(rd.Type+" action "+ad.Name, ad.InputSchema, output, nil, opts, func(h *Harness, input map[string]any) (map[string]any, error) {
+				return h.Action(resource, ad.Name, input)
+			})
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, t)
+		}
+	}
+
+	return targets, nil
+}
+
+// newFuzzTarget returns the fuzzTarget of an input schema. The kept properties are set by the App when
+// they are omitted from the input, so deleting them is not a violation.
+func newFuzzTarget(name string, inputSchema *structpb.Struct, output *app.JSONSchema, kept []string, opts FuzzOptions, invoke func(*Harness, map[string]any) (map[string]any, error)) (*fuzzTarget, error) {
+	input, err := compileSchema(inputSchema, opts.SchemaOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%s input schema: %w", name, err)
+	}
+
+	raw := inputSchema.AsMap()
+
+	return &fuzzTarget{
+		name: name,
+		input: func(mode uint8, seed uint64, b []byte) (map[string]any, bool, bool) {
+			switch mode % fuzzModes {
+			case fuzzValid:
+				return example(input, seed), false, true
+			case fuzzViolation:
+				in := example(input, seed)
+				violated := violate(raw, in, kept, seed)
+				return in, violated, true
+			default:
+				var in map[string]any
+				if err := json.Unmarshal(b, &in); err != nil || in == nil {
+					return nil, false, false
+				}
+				// Values that can't be represented in protobuf are rejected before reaching the App.
+				if _, err := structpb.NewStruct(in); err != nil {
+					return nil, false, false
+				}
+				return in, rawViolation(raw, input, in, kept), true
+			}
+		},
+		invoke: invoke,
+		output: output,
+	}, nil
+}
+
+// example returns a random valid example of the schema, or the minimal example when no random one can be generated.
+func example(s *app.JSONSchema, seed uint64) map[string]any {
+	if e, err := s.RandomExample(seed); err == nil {
+		return e
+	}
+
+	if e, err := s.MinimalExample(); err == nil {
+		return e
+	}
+
+	return map[string]any{}
+}
+
+// fuzzUnknownProperty is the property added to inputs that don't allow additional properties.
+const fuzzUnknownProperty = "apptest_fuzz_unknown"
+
+// violate changes the input so that it does not validate against the schema, in a way that neither
+// default values, type coercion nor the kept properties can repair. It reports false if the schema
+// accepts every change it can make.
+func violate(schema, input map[string]any, kept []string, seed uint64) bool {
+	properties, _ := schema["properties"].(map[string]any)
+
+	var violations []func()
+
+	if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+		violations = append(violations, func() {
+			input[fuzzUnknownProperty] = "unknown"
+		})
+	}
+
+	required, _ := schema["required"].([]any)
+	for _, r := range required {
+		name, _ := r.(string)
+		if p, _ := properties[name].(map[string]any); p != nil && p["default"] != nil {
+			continue
+		}
+		if slices.Contains(kept, name) {
+			continue
+		}
+
+		violations = append(violations, func() {
+			delete(input, name)
+		})
+	}
+
+	// Properties can't be objects, so an object value never validates against a typed property.
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		if p, _ := properties[name].(map[string]any); p == nil || p["type"] == nil {
+			continue
+		}
+
+		violations = append(violations, func() {
+			input[name] = map[string]any{"apptest_fuzz": true}
+		})
+	}
+
+	if len(violations) == 0 {
+		return false
+	}
+
+	violations[seed%uint64(len(violations))]()
+	return true
+}
+
+// rawViolation reports whether an input decoded from the fuzzing engine has a violation that the App
+// can't repair: it doesn't validate against the schema once the default values are set, none of its
+// values has another scalar type than its property, which type coercion may convert, and none of the
+// kept properties is missing. Schemas with a $ref or allOf at the root are not checked.
+func rawViolation(schema map[string]any, s *app.JSONSchema, input map[string]any, kept []string) bool {
+	if schema["$ref"] != nil || schema["allOf"] != nil {
+		return false
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	in := maps.Clone(input)
+	for name, p := range properties {
+		p, _ := p.(map[string]any)
+		if p == nil {
+			continue
+		}
+
+		v, ok := in[name]
+		if !ok {
+			if slices.Contains(kept, name) {
+				return false
+			}
+			if d, ok := p["default"]; ok {
+				in[name] = d
+			}
+			continue
+		}
+
+		if coercible(p["type"], v) {
+			return false
+		}
+	}
+
+	return s.Validate(in) != nil
+}
+
+// coercible reports whether the value is a scalar of another type than the scalar types of the property.
+func coercible(types, v any) bool {
+	var have []string
+	switch v.(type) {
+	case string:
+		have = []string{"string"}
+	case float64:
+		have = []string{"number", "integer"}
+	case bool:
+		have = []string{"boolean"}
+	default:
+		return false
+	}
+
+	var want []string
+	switch t := types.(type) {
+	case string:
+		want = []string{t}
+	case []any:
+		for _, e := range t {
+			if name, ok := e.(string); ok {
+				want = append(want, name)
+			}
+		}
+	}
+
+	var scalar bool
+	for _, t := range want {
+		if slices.Contains(have, t) {
+			return false
+		}
+		scalar = scalar || slices.Contains([]string{"string", "number", "integer", "boolean"}, t)
+	}
+
+	return scalar
+}
+
+// immutableProperties returns the properties of the input schema marked with x-tempest-immutable
+// in the input schema or the properties schema.
+func immutableProperties(propertiesSchema, inputSchema *structpb.Struct) []string {
+	inputProperties := inputSchema.GetFields()["properties"].GetStructValue().GetFields()
+
+	var names []string
+	for _, s := range []*structpb.Struct{propertiesSchema, inputSchema} {
+		for name, p := range s.GetFields()["properties"].GetStructValue().GetFields() {
+			if !p.GetStructValue().GetFields()[app.AnnotationImmutable].GetBoolValue() {
+				continue
+			}
+			if _, ok := inputProperties[name]; ok && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// compileSchema compiles a schema from a DescribeResponse.
+func compileSchema(s *structpb.Struct, opts []app.SchemaOption) (*app.JSONSchema, error) {
+	if s == nil {
+		return nil, errors.New("schema is not described")
+	}
+
+	raw, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+
+	return app.ParseJSONSchema(raw, opts...)
+}
@@ -0,0 +1,186 @@
+package apptest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+func fuzzBucketApp(f *testing.F) (*app.App, FuzzOptions) {
+	a, _ := newBucketApp(f)
+
+	existing, err := New(f, a).Create("bucket", map[string]any{"name": "existing"})
+	require.NoError(f, err)
+
+	return a, FuzzOptions{Resources: map[string]*app.Resource{"bucket": existing}}
+}
+
+func FuzzHarness(f *testing.F) {
+	a, opts := fuzzBucketApp(f)
+
+	Fuzz(f, a, opts)
+}
+
+func FuzzHarnessHTTP(f *testing.F) {
+	a, opts := fuzzBucketApp(f)
+	opts.HTTP = true
+
+	Fuzz(f, a, opts)
+}
+
+func TestViolate(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"size": map[string]any{"type": "integer", "default": float64(1)},
+			"any":  map[string]any{},
+		},
+		"required":             []any{"name", "size"},
+		"additionalProperties": false,
+	}
+
+	testCases := []struct {
+		desc     string
+		schema   map[string]any
+		kept     []string
+		seed     uint64
+		want     map[string]any
+		violated bool
+	}{
+		{
+			desc:     "OK - Unknown Property",
+			schema:   schema,
+			seed:     0,
+			want:     map[string]any{"name": "a", "size": float64(1), fuzzUnknownProperty: "unknown"},
+			violated: true,
+		},
+		{
+			desc:     "OK - Missing Required Property",
+			schema:   schema,
+			seed:     1,
+			want:     map[string]any{"size": float64(1)},
+			violated: true,
+		},
+		{
+			desc:     "OK - Kept Property Not Deleted",
+			schema:   schema,
+			kept:     []string{"name"},
+			seed:     1,
+			want:     map[string]any{"name": map[string]any{"apptest_fuzz": true}, "size": float64(1)},
+			violated: true,
+		},
+		{
+			desc:     "OK - Object Property",
+			schema:   schema,
+			seed:     2,
+			want:     map[string]any{"name": map[string]any{"apptest_fuzz": true}, "size": float64(1)},
+			violated: true,
+		},
+		{
+			desc:   "OK - No Violation",
+			schema: map[string]any{"type": "object", "additionalProperties": true},
+			want:   map[string]any{"name": "a", "size": float64(1)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := map[string]any{"name": "a", "size": float64(1)}
+
+			assert.Equal(t, tc.violated, violate(tc.schema, input, tc.kept, tc.seed))
+			assert.Equal(t, tc.want, input)
+		})
+	}
+}
+
+func TestFuzzTargets(t *testing.T) {
+	a, _ := newBucketApp(t)
+
+	res, err := New(t, a).Describe()
+	require.NoError(t, err)
+
+	targets, err := fuzzTargets(res, FuzzOptions{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, tg := range targets {
+		names = append(names, tg.name)
+	}
+	assert.Equal(t, []string{"bucket create", "bucket update", "bucket action empty"}, names)
+
+	properties, err := compileSchema(res.ResourceDefinitions[0].CreateInputSchema, nil)
+	require.NoError(t, err)
+
+	for seed := range uint64(8) {
+		input, violated, ok := targets[0].input(fuzzValid, seed, nil)
+		require.True(t, ok)
+		assert.False(t, violated)
+		assert.NoError(t, properties.Validate(input))
+
+		input, violated, ok = targets[0].input(fuzzViolation, seed, nil)
+		require.True(t, ok)
+		assert.True(t, violated)
+		assert.Error(t, properties.Validate(input))
+	}
+
+	_, _, ok := targets[0].input(fuzzJSON, 0, []byte("not json"))
+	assert.False(t, ok)
+
+	for raw, want := range map[string]bool{
+		`{"name": "a"}`:              false,
+		`{"size": 2}`:                true,
+		`{"name": "a", "size": 1.5}`: true,
+		`{"name": "a", "size": "2"}`: false,
+		`{"name": "a", "other": 1}`:  true,
+	} {
+		_, violated, ok := targets[0].input(fuzzJSON, 0, []byte(raw))
+		require.True(t, ok, raw)
+		assert.Equal(t, want, violated, raw)
+	}
+}
+
+func TestFuzzTargetsImmutable(t *testing.T) {
+	s := &bucketStore{buckets: make(map[string]map[string]any)}
+	properties := app.MustParseJSONSchema([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "x-tempest-immutable": true}
+		},
+		"required": ["name"],
+		"additionalProperties": false
+	}`))
+
+	rd, err := app.NewResource("bucket").
+		Properties(properties).
+		Create(s.create, properties).
+		Update(s.update, properties).
+		Build()
+	require.NoError(t, err)
+
+	res, err := New(t, app.New(app.WithResourceDefinition(rd))).Describe()
+	require.NoError(t, err)
+
+	targets, err := fuzzTargets(res, FuzzOptions{})
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+
+	// The App sets the omitted immutable name of an update input to its stored value.
+	for seed := range uint64(8) {
+		input, violated, ok := targets[1].input(fuzzViolation, seed, nil)
+		require.True(t, ok)
+		if violated {
+			assert.Contains(t, input, "name")
+		}
+	}
+
+	_, violated, ok := targets[1].input(fuzzJSON, 0, []byte(`{}`))
+	require.True(t, ok)
+	assert.False(t, violated)
+
+	_, violated, ok = targets[0].input(fuzzJSON, 0, []byte(`{}`))
+	require.True(t, ok)
+	assert.True(t, violated)
+}
@@ -0,0 +1,100 @@
+package apptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+	"github.com/tempestdx/sdk-go/app"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// UpdateEnv is the environment variable that makes AssertDescribeGolden write the golden files
+// instead of comparing them, when set to a true value such as 1.
+const UpdateEnv = "APPTEST_UPDATE"
+
+// updateGolden reports whether the golden files must be updated.
+func updateGolden() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return update
+}
+
+// MarshalDescribe returns the JSON encoding of a DescribeResponse, with sorted object keys,
+// ResourceDefinitions sorted by Type and Actions sorted by Name, so that the output only changes
+// when the definitions do. Fields with zero values, such as unsupported operation flags, are included.
+func MarshalDescribe(res *appv1.DescribeResponse) ([]byte, error) {
+	res = proto.CloneOf(res)
+
+	slices.SortFunc(res.ResourceDefinitions, func(a, b *appv1.ResourceDefinition) int {
+		return strings.Compare(a.Type, b.Type)
+	})
+	for _, rd := range res.ResourceDefinitions {
+		slices.SortFunc(rd.Actions, func(a, b *appv1.ActionDefinition) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	// protojson output is deliberately unstable, so it is only used to convert the message to JSON values.
+	b, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("marshal describe response: %w", err)
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal describe response: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("encode describe response: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AssertDescribeGolden compares the Describe output of the App, as encoded by MarshalDescribe,
+// with the golden file at path, conventionally under testdata.
+//
+// Run the tests with APPTEST_UPDATE=1 to write the current output to the golden file, then review
+// the diff of the file before committing it:
+//
+//	APPTEST_UPDATE=1 go test ./... -run TestDescribe
+func AssertDescribeGolden(t testing.TB, a *app.App, path string) {
+	t.Helper()
+
+	res, err := New(t, a).Describe()
+	require.NoError(t, err, "describe")
+
+	got, err := MarshalDescribe(res)
+	require.NoError(t, err)
+
+	if updateGolden() {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist, run the test with %s=1 to create it", path, UpdateEnv)
+	}
+	require.NoError(t, err)
+
+	assert.Equal(t, string(want), string(got), "Describe output differs from %s, run the test with %s=1 to accept the changes", path, UpdateEnv)
+}
@@ -0,0 +1,60 @@
+package apptest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appv1 "github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1"
+)
+
+func TestAssertDescribeGolden(t *testing.T) {
+	a, _ := newBucketApp(t)
+
+	AssertDescribeGolden(t, a, "testdata/describe.golden.json")
+}
+
+func TestAssertDescribeGoldenUpdate(t *testing.T) {
+	a, _ := newBucketApp(t)
+	path := filepath.Join(t.TempDir(), "testdata", "describe.golden.json")
+
+	t.Setenv(UpdateEnv, "1")
+	AssertDescribeGolden(t, a, path)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/describe.golden.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestMarshalDescribe(t *testing.T) {
+	got, err := MarshalDescribe(&appv1.DescribeResponse{})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"resource_definitions\": []\n}\n", string(got))
+
+	res := &appv1.DescribeResponse{
+		ResourceDefinitions: []*appv1.ResourceDefinition{
+			{
+				Type: "b",
+				Actions: []*appv1.ActionDefinition{
+					{Name: "y"},
+					{Name: "x"},
+				},
+			},
+			{Type: "a"},
+		},
+	}
+
+	got, err = MarshalDescribe(res)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(got), `"type": "a"`), strings.Index(string(got), `"type": "b"`))
+	assert.Less(t, strings.Index(string(got), `"name": "x"`), strings.Index(string(got), `"name": "y"`))
+
+	// The response itself is not reordered.
+	assert.Equal(t, "b", res.ResourceDefinitions[0].Type)
+	assert.Equal(t, "y", res.ResourceDefinitions[0].Actions[0].Name)
+}
@@ -0,0 +1,452 @@
+package apptest
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// RecordEnv is the environment variable that makes Recorders in ModeAuto record the cassettes against
+// the live services, when set to a true value such as 1.
+const RecordEnv = "APPTEST_RECORD"
+
+// RecorderMode selects whether a Recorder records or replays interactions.
+type RecorderMode int
+
+const (
+	// ModeAuto records when the tests run with APPTEST_RECORD=1, and replays otherwise.
+	ModeAuto RecorderMode = iota
+	// ModeReplay serves the responses recorded in the cassette, without calling the live service.
+	ModeReplay
+	// ModeRecord calls the live service and writes the interactions to the cassette when the test finishes.
+	ModeRecord
+)
+
+// BodyMatcher reports whether the body of a request matches the body of a recorded request.
+// Both bodies have their secrets redacted.
+type BodyMatcher func(recorded, actual []byte) bool
+
+// ExactBody matches bodies that are byte for byte equal.
+func ExactBody(recorded, actual []byte) bool {
+	return bytes.Equal(recorded, actual)
+}
+
+// AnyBody matches every body.
+func AnyBody(_, _ []byte) bool {
+	return true
+}
+
+// JSONBody matches bodies that are equal JSON values, regardless of formatting and of the order of object keys,
+// once the values at the JSON pointers are removed from both. Use the pointers to ignore values that change
+// on every run, such as timestamps or idempotency keys. Bodies that are not JSON must be byte for byte equal.
+func JSONBody(ignore ...string) BodyMatcher {
+	return func(recorded, actual []byte) bool {
+		var r, a any
+		if json.Unmarshal(recorded, &r) != nil || json.Unmarshal(actual, &a) != nil {
+			return bytes.Equal(recorded, actual)
+		}
+
+		for _, p := range ignore {
+			r = removePointer(r, p)
+			a = removePointer(a, p)
+		}
+
+		return reflect.DeepEqual(r, a)
+	}
+}
+
+// removePointer removes the value at the JSON pointer from a decoded JSON value.
+func removePointer(v any, pointer string) any {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+
+	var remove func(v any, tokens []string) any
+	remove = func(v any, tokens []string) any {
+		switch val := v.(type) {
+		case map[string]any:
+			if len(tokens) == 1 {
+				delete(val, tokens[0])
+			} else if child, ok := val[tokens[0]]; ok {
+				val[tokens[0]] = remove(child, tokens[1:])
+			}
+		case []any:
+			i, err := strconv.Atoi(tokens[0])
+			if err != nil || i < 0 || i >= len(val) {
+				return v
+			}
+			if len(tokens) == 1 {
+				return slices.Delete(val, i, i+1)
+			}
+			val[i] = remove(val[i], tokens[1:])
+		}
+		return v
+	}
+
+	return remove(v, tokens)
+}
+
+// RecorderOptions configures a Recorder.
+type RecorderOptions struct {
+	// Mode selects whether the Recorder records or replays. Defaults to ModeAuto.
+	Mode RecorderMode
+	// Transport calls the live service in ModeRecord. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Environment is the environment of the handlers under test. The values of its secrets, certificates
+	// and private keys are redacted from the cassette, and replaced by the current value when replayed.
+	Environment []app.EnvironmentVariable
+	// Secrets are other values to redact, keyed by the name used in their placeholder.
+	Secrets map[string]string
+	// RedactHeaders are the headers whose values are redacted.
+	// Defaults to Authorization, Proxy-Authorization, Cookie, Set-Cookie and X-Api-Key.
+	RedactHeaders []string
+	// MatchBody matches the bodies of the requests with the recorded ones. Defaults to JSONBody().
+	MatchBody BodyMatcher
+}
+
+// Cassette is the file format of the interactions recorded by a Recorder.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request of an Interaction.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response of an Interaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a request or response body. It is encoded as a string when it is valid UTF-8, and in base64 otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("body must be a string or an object with a base64 field: %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("decode body: %w", err)
+	}
+	*b = decoded
+
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records the interactions of handlers with the services they call
+// to a cassette file, and replays them in later runs. Use it as the Transport of the HTTP clients of the
+// handlers under test:
+//
+//	rec := apptest.NewRecorder(t, "testdata/create_bucket.json", apptest.RecorderOptions{
+//		Environment: h.Environment,
+//	})
+//	client := &http.Client{Transport: rec}
+//
+// Run the tests with APPTEST_RECORD=1, against the live services, to record the cassettes, or set
+// RecorderOptions.Mode to ModeRecord. Otherwise, the requests are matched by method, URL and body with the recorded interactions, in order,
+// and requests that match no interaction fail.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+	matchBody BodyMatcher
+	redactor  redactor
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a Recorder for the cassette at path, conventionally under testdata.
+// In ModeRecord, the cassette is written when the test finishes.
+func NewRecorder(tb testing.TB, path string, opts RecorderOptions) *Recorder {
+	tb.Helper()
+
+	r := &Recorder{
+		path:      path,
+		mode:      opts.Mode,
+		transport: cmp.Or[http.RoundTripper](opts.Transport, http.DefaultTransport),
+		matchBody: opts.MatchBody,
+		redactor:  newRedactor(opts),
+	}
+	if r.mode == ModeAuto {
+		r.mode = ModeReplay
+		if record, _ := strconv.ParseBool(os.Getenv(RecordEnv)); record {
+			r.mode = ModeRecord
+		}
+	}
+	if r.matchBody == nil {
+		r.matchBody = JSONBody()
+	}
+
+	if r.mode == ModeRecord {
+		tb.Cleanup(func() {
+			if err := r.save(); err != nil {
+				tb.Errorf("save cassette: %v", err)
+			}
+		})
+		return r
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("read cassette, run the test with %s=1 to record it: %v", RecordEnv, err)
+	}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		tb.Fatalf("decode cassette %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r
+}
+
+// Client returns an http.Client that uses the Recorder as Transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    r.redactor.redact(req.URL.String()),
+		Header: r.redactor.header(req.Header),
+		Body:   Body(r.redactor.redact(string(body))),
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(&res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     r.redactor.header(res.Header),
+			Body:       Body(r.redactor.redact(string(body))),
+		},
+	})
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != recorded.Method || interaction.Request.URL != recorded.URL ||
+			!r.matchBody(interaction.Request.Body, recorded.Body) {
+			continue
+		}
+		r.used[i] = true
+
+		body := r.redactor.restore(string(interaction.Response.Body))
+
+		// The length of the body changes when secrets are restored.
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Del("Content-Length")
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no interaction recorded in %s matches %s %s", r.path, recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// readBody reads a body and replaces it with a reader of the same bytes.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*body)
+	closeErr := (*body).Close()
+	if err := errors.Join(err, closeErr); err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+// redactedHeader is the value of the headers that are redacted.
+const redactedHeader = "REDACTED"
+
+type secret struct {
+	value       string
+	placeholder string
+}
+
+type redactor struct {
+	secrets []secret
+	headers []string
+}
+
+func newRedactor(opts RecorderOptions) redactor {
+	var r redactor
+
+	for _, v := range opts.Environment {
+		switch v.Type {
+		case app.ENVIRONMENT_VARIABLE_TYPE_SECRET, app.ENVIRONMENT_VARIABLE_TYPE_CERTIFICATE, app.ENVIRONMENT_VARIABLE_TYPE_PRIVATE_KEY:
+			if v.Value != "" {
+				r.secrets = append(r.secrets, secret{value: v.Value, placeholder: "{{" + v.Key + "}}"})
+			}
+		}
+	}
+	for k, v := range opts.Secrets {
+		if v != "" {
+			r.secrets = append(r.secrets, secret{value: v, placeholder: "{{" + k + "}}"})
+		}
+	}
+
+	// Longer secrets are replaced first, so that a secret containing another one is redacted as a whole.
+	slices.SortFunc(r.secrets, func(a, b secret) int {
+		return cmp.Or(cmp.Compare(len(b.value), len(a.value)), strings.Compare(a.placeholder, b.placeholder))
+	})
+
+	r.headers = opts.RedactHeaders
+	if r.headers == nil {
+		r.headers = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+	}
+
+	return r
+}
+
+func (r redactor) redact(s string) string {
+	for _, sec := range r.secrets {
+		s = strings.ReplaceAll(s, sec.value, sec.placeholder)
+	}
+
+	return s
+}
+
+// restore replaces the placeholders with the current values of the secrets.
+func (r redactor) restore(s string) string {
+	for _, sec := range r.secrets {
+		s = strings.ReplaceAll(s, sec.placeholder, sec.value)
+	}
+
+	return s
+}
+
+func (r redactor) header(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	redacted := make(http.Header, len(h))
+	for k, values := range h {
+		for _, v := range values {
+			redacted.Add(k, r.redact(v))
+		}
+	}
+
+	for _, k := range r.headers {
+		if redacted.Get(k) != "" {
+			redacted.Set(k, redactedHeader)
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,218 @@
+package apptest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// upstream is a service that echoes the token and the body of the request.
+func upstream(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"` + r.Header.Get("X-Token") + `","request":` + string(b) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func post(t *testing.T, c *http.Client, url, token, body string) (*http.Response, string, error) {
+	req, err := http.NewRequest(http.MethodPost, url+"/items?token="+token, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Token", token)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res, string(b), nil
+}
+
+func TestRecorder(t *testing.T) {
+	srv := upstream(t)
+	cassette := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	t.Run("record", func(t *testing.T) {
+		rec := NewRecorder(t, cassette, RecorderOptions{
+			Mode:        ModeRecord,
+			Environment: []app.EnvironmentVariable{Secret("TOKEN", "live-token-1234"), Variable("REGION", "eu")},
+		})
+
+		res, body, err := post(t, rec.Client(), srv.URL, "live-token-1234", `{"name": "a", "region": "eu"}`)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.JSONEq(t, `{"token": "live-token-1234", "request": {"name": "a", "region": "eu"}}`, body)
+	})
+
+	b, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "live-token-1234")
+	assert.Contains(t, string(b), "{{TOKEN}}")
+	assert.Contains(t, string(b), `"REDACTED"`)
+	assert.NotContains(t, string(b), "session=abc")
+	assert.Contains(t, string(b), "eu", "variables are not redacted")
+
+	// The upstream service is not called when replaying.
+	srv.Close()
+
+	testCases := []struct {
+		desc  string
+		token string
+		body  string
+		opts  RecorderOptions
+		want  string
+		err   string
+	}{
+		{
+			desc:  "OK - Secret Restored",
+			token: "ci-token-5678",
+			body:  `{"region": "eu", "name": "a"}`,
+			want:  `{"token": "ci-token-5678", "request": {"name": "a", "region": "eu"}}`,
+		},
+		{
+			desc:  "OK - Ignored Field",
+			token: "ci-token-5678",
+			body:  `{"region": "eu", "name": "b"}`,
+			opts:  RecorderOptions{MatchBody: JSONBody("/name")},
+			want:  `{"token": "ci-token-5678", "request": {"name": "a", "region": "eu"}}`,
+		},
+		{
+			desc:  "ERR - Body Mismatch",
+			token: "ci-token-5678",
+			body:  `{"region": "eu", "name": "b"}`,
+			err:   "no interaction recorded in " + cassette + " matches POST " + srv.URL + "/items?token={{TOKEN}}",
+		},
+		{
+			desc:  "ERR - Exact Body Mismatch",
+			token: "ci-token-5678",
+			body:  `{"region": "eu", "name": "a"}`,
+			opts:  RecorderOptions{MatchBody: ExactBody},
+			err:   "no interaction recorded",
+		},
+		{
+			desc:  "ERR - URL Mismatch",
+			token: "unknown",
+			body:  `{"region": "eu", "name": "a"}`,
+			err:   "no interaction recorded",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.opts.Mode = ModeReplay
+			tc.opts.Environment = []app.EnvironmentVariable{Secret("TOKEN", "ci-token-5678")}
+			rec := NewRecorder(t, cassette, tc.opts)
+
+			res, body, err := post(t, rec.Client(), srv.URL, tc.token, tc.body)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+			assert.JSONEq(t, tc.want, body)
+
+			// Each interaction is replayed once.
+			_, _, err = post(t, rec.Client(), srv.URL, tc.token, tc.body)
+			assert.ErrorContains(t, err, "no interaction recorded")
+		})
+	}
+}
+
+func TestRecorderAutoMode(t *testing.T) {
+	srv := upstream(t)
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(RecordEnv, "1")
+		rec := NewRecorder(t, cassette, RecorderOptions{})
+
+		_, _, err := post(t, rec.Client(), srv.URL, "token", `{"name": "a"}`)
+		require.NoError(t, err)
+	})
+
+	require.FileExists(t, cassette)
+	srv.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv(RecordEnv, "")
+		rec := NewRecorder(t, cassette, RecorderOptions{})
+
+		_, body, err := post(t, rec.Client(), srv.URL, "token", `{"name": "a"}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"token": "token", "request": {"name": "a"}}`, body)
+	})
+}
+
+func TestRemovePointer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		pointer string
+		want    any
+	}{
+		{
+			desc:    "OK - Top Level",
+			pointer: "/id",
+			want:    map[string]any{"items": []any{map[string]any{"a/b": 1.0, "ts": 2.0}, 3.0}},
+		},
+		{
+			desc:    "OK - Nested",
+			pointer: "/items/0/ts",
+			want:    map[string]any{"id": "x", "items": []any{map[string]any{"a/b": 1.0}, 3.0}},
+		},
+		{
+			desc:    "OK - Escaped",
+			pointer: "/items/0/a~1b",
+			want:    map[string]any{"id": "x", "items": []any{map[string]any{"ts": 2.0}, 3.0}},
+		},
+		{
+			desc:    "OK - Array Element",
+			pointer: "/items/1",
+			want:    map[string]any{"id": "x", "items": []any{map[string]any{"a/b": 1.0, "ts": 2.0}}},
+		},
+		{
+			desc:    "OK - Missing",
+			pointer: "/missing/0",
+			want:    map[string]any{"id": "x", "items": []any{map[string]any{"a/b": 1.0, "ts": 2.0}, 3.0}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			v := map[string]any{"id": "x", "items": []any{map[string]any{"a/b": 1.0, "ts": 2.0}, 3.0}}
+
+			assert.Equal(t, tc.want, removePointer(v, tc.pointer))
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	for _, b := range []Body{Body("text"), Body{0xff, 0x00}} {
+		encoded, err := b.MarshalJSON()
+		require.NoError(t, err)
+
+		var decoded Body
+		require.NoError(t, decoded.UnmarshalJSON(encoded))
+		assert.Equal(t, b, decoded)
+	}
+}
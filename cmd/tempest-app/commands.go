@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tempestdx/sdk-go/app"
+	"github.com/tempestdx/sdk-go/appdoc"
+)
+
+// command is a subcommand of tempest-app.
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = []*command{
+	{name: "describe", usage: "describe [type]", help: "print the resource definitions and their schemas", run: describe},
+	{name: "docs", usage: "docs <dir>", help: "write the documentation of the resource types to the directory", run: docs},
+	{name: "create", usage: "create <type>", help: "create a resource with the -input", run: create},
+	{name: "read", usage: "read <type> <id>", help: "read a resource", run: read},
+	{name: "update", usage: "update <type> <id>", help: "update a resource with the -input", run: update},
+	{name: "delete", usage: "delete <type> <id>", help: "delete a resource", run: remove},
+	{name: "list", usage: "list <type>", help: "list the resources, following every page unless -page is set", run: list},
+	{name: "action", usage: "action <type> <id> <name>", help: "execute an action on a resource with the -input", run: action},
+	{name: "health", usage: "health <type>", help: "check the health of a resource type", run: health},
+}
+
+func findCommand(name string) (*command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return nil, false
+}
+
+// parseArgs parses the flags of a command, which can be given before, between or after its arguments.
+// It returns a usage error unless the required arguments, and at most the optional ones, are given.
+func parseArgs(c *cli, fs *flag.FlagSet, args []string, required, optional int) ([]string, error) {
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: tempest-app %s\n", c.command.usage)
+		fs.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{msg: err.Error()}
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) < required || len(positional) > required+optional {
+		return nil, usagef("usage: tempest-app %s", c.command.usage)
+	}
+
+	return positional, nil
+}
+
+// jsonFlags are a pair of flags that give a JSON object inline or in a file.
+type jsonFlags struct {
+	name  string
+	value string
+	file  string
+}
+
+func newJSONFlags(fs *flag.FlagSet, name, help string) *jsonFlags {
+	f := &jsonFlags{name: name}
+	fs.StringVar(&f.value, name, "", "`JSON` object of the "+help)
+	fs.StringVar(&f.file, name+"-file", "", "`file` with the JSON object of the "+help+", - for stdin")
+
+	return f
+}
+
+// object returns the JSON object of the flags, or nil when neither is set.
+func (f *jsonFlags) object(stdin io.Reader) (map[string]any, error) {
+	var data []byte
+	switch {
+	case f.value != "" && f.file != "":
+		return nil, usagef("-%s and -%s-file are mutually exclusive", f.name, f.name)
+	case f.value != "":
+		data = []byte(f.value)
+	case f.file == "-":
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read -%s-file from stdin: %w", f.name, err)
+		}
+		data = b
+	case f.file != "":
+		b, err := os.ReadFile(f.file)
+		if err != nil {
+			return nil, fmt.Errorf("read -%s-file: %w", f.name, err)
+		}
+		data = b
+	default:
+		return nil, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("-%s is not a JSON object: %w", f.name, err)
+	}
+
+	return m, nil
+}
+
+func (c *cli) operationRequest(r *app.Resource, input map[string]any) *app.OperationRequest {
+	return &app.OperationRequest{
+		Metadata:    c.metadata,
+		Resource:    r,
+		Input:       input,
+		Environment: c.environment,
+	}
+}
+
+// resourceArgs parses the arguments of the commands on an existing resource: its type, its ID and
+// the extra arguments of the command, which are returned.
+func resourceArgs(c *cli, fs *flag.FlagSet, args []string, extra int) (*app.Resource, []string, error) {
+	properties := newJSONFlags(fs, "properties", "current properties of the resource")
+
+	positional, err := parseArgs(c, fs, args, 2+extra, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	props, err := properties.object(c.stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &app.Resource{
+		Type:       positional[0],
+		ExternalID: positional[1],
+		Properties: props,
+	}, positional[2:], nil
+}
+
+func describe(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	positional, err := parseArgs(c, fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	descriptors, err := c.client.Describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, d := range descriptors {
+		if len(positional) == 1 && d.Type != positional[0] {
+			continue
+		}
+		if found {
+			fmt.Fprintln(c.stdout)
+		}
+		found = true
+
+		if err := printDescriptor(c.stdout, d); err != nil {
+			return err
+		}
+	}
+
+	if len(positional) == 1 && !found {
+		return fmt.Errorf("the app has no resource type %q", positional[0])
+	}
+
+	return nil
+}
+
+func docs(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	html := fs.Bool("html", false, "write HTML pages instead of Markdown")
+	title := fs.String("title", "", "`title` of the index")
+	examples := fs.Bool("examples", false, "add an example of every input")
+
+	positional, err := parseArgs(c, fs, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	descriptors, err := c.client.Describe(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := appdoc.Options{Title: *title, Examples: *examples}
+	if *html {
+		opts.Format = appdoc.FormatHTML
+	}
+
+	return appdoc.WriteSite(positional[0], descriptors, opts)
+}
+
+func create(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	input := newJSONFlags(fs, "input", "input")
+
+	positional, err := parseArgs(c, fs, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	in, err := input.object(c.stdin)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.client.Create(ctx, c.operationRequest(&app.Resource{Type: positional[0]}, in))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, resourceJSON(r))
+}
+
+func read(ctx context.Context, c *cli, args []string) error {
+	r, _, err := resourceArgs(c, flag.NewFlagSet("read", flag.ContinueOnError), args, 0)
+	if err != nil {
+		return err
+	}
+
+	r, err = c.client.Read(ctx, c.operationRequest(r, nil))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, resourceJSON(r))
+}
+
+func update(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	input := newJSONFlags(fs, "input", "input")
+
+	r, _, err := resourceArgs(c, fs, args, 0)
+	if err != nil {
+		return err
+	}
+
+	in, err := input.object(c.stdin)
+	if err != nil {
+		return err
+	}
+
+	r, err = c.client.Update(ctx, c.operationRequest(r, in))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, resourceJSON(r))
+}
+
+func remove(ctx context.Context, c *cli, args []string) error {
+	r, _, err := resourceArgs(c, flag.NewFlagSet("delete", flag.ContinueOnError), args, 0)
+	if err != nil {
+		return err
+	}
+
+	r, err = c.client.Delete(ctx, c.operationRequest(r, nil))
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, resourceJSON(r))
+}
+
+func list(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	page := fs.Bool("page", false, "list a single page and print the token of the next one")
+	next := fs.String("next", "", "`token` of the page to start from")
+
+	positional, err := parseArgs(c, fs, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	req := &app.ListRequest{
+		Metadata: c.metadata,
+		Resource: &app.Resource{Type: positional[0]},
+		Next:     *next,
+	}
+
+	if *page {
+		res, err := c.client.ListPage(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(c.stdout, map[string]any{
+			"resources": resourcesJSON(res.Resources),
+			"next":      res.Next,
+		})
+	}
+
+	resources, err := c.client.List(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, resourcesJSON(resources))
+}
+
+func action(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("action", flag.ContinueOnError)
+	input := newJSONFlags(fs, "input", "input")
+
+	r, positional, err := resourceArgs(c, fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	in, err := input.object(c.stdin)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ExecuteAction(ctx, &app.ActionRequest{
+		Metadata:    c.metadata,
+		Resource:    r,
+		Action:      positional[0],
+		Input:       in,
+		Environment: c.environment,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, res.Output)
+}
+
+func health(ctx context.Context, c *cli, args []string) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	positional, err := parseArgs(c, fs, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.HealthCheck(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return printJSON(c.stdout, map[string]any{
+		"status":  res.Status.String(),
+		"message": res.Message,
+	})
+}
+
+// indent prefixes every non-empty line of s.
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
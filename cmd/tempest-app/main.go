@@ -0,0 +1,185 @@
+// Command tempest-app invokes a Tempest app running locally, so that it can be tried without deploying it to Tempest.
+//
+// The app is called at the URL of the -url flag, or started with the command of the -exec flag.
+// The command must serve the app on the port of the PORT environment variable, for example with:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle(appv1connect.NewAppServiceHandler(a))
+//	http.ListenAndServe(":"+os.Getenv("PORT"), mux)
+//
+// Usage:
+//
+//	tempest-app [flags] <command> [arguments]
+//
+// The commands are:
+//
+//	describe [type]                    print the resource definitions and their schemas
+//	docs <dir>                         write the documentation of the resource types to the directory
+//	create <type>                      create a resource with the -input
+//	read <type> <id>                   read a resource
+//	update <type> <id>                 update a resource with the -input
+//	delete <type> <id>                 delete a resource
+//	list <type>                        list the resources, following every page unless -page is set
+//	action <type> <id> <name>          execute an action on a resource with the -input
+//	health <type>                      check the health of a resource type
+//
+// Inputs are JSON objects, given with -input or read from the file of -input-file ("-" for stdin).
+// Resources, lists, action outputs and health checks are printed as JSON.
+//
+// Examples:
+//
+//	tempest-app -exec "go run ./cmd/server" describe
+//	tempest-app -url http://localhost:8080 create bucket -input '{"name": "logs"}'
+//	tempest-app -secret TOKEN=$TOKEN action bucket logs empty -input-file empty.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.As(err, new(usageError)):
+		fmt.Fprintf(os.Stderr, "tempest-app: %v\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "tempest-app: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usageError is returned for invalid commands, arguments and flags.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// cli holds the state shared by the commands.
+type cli struct {
+	command     *command
+	client      *app.Client
+	metadata    *app.Metadata
+	environment map[string]app.EnvironmentVariable
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// envFlag collects KEY=VALUE environment variables of a type.
+type envFlag struct {
+	vars map[string]app.EnvironmentVariable
+	typ  app.EnvironmentVariableType
+}
+
+func (f *envFlag) String() string {
+	return ""
+}
+
+func (f *envFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%q is not KEY=VALUE", s)
+	}
+
+	f.vars[key] = app.EnvironmentVariable{Key: key, Value: value, Type: f.typ}
+	return nil
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	c := &cli{
+		environment: make(map[string]app.EnvironmentVariable),
+		stdin:       stdin,
+		stdout:      stdout,
+		stderr:      stderr,
+	}
+
+	fs := flag.NewFlagSet("tempest-app", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tempest-app [flags] <command> [arguments]")
+		fmt.Fprintln(stderr, "\ncommands:")
+		for _, cmd := range commands {
+			fmt.Fprintf(stderr, "  %-28s %s\n", cmd.usage, cmd.help)
+		}
+		fmt.Fprintln(stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+
+	defaultURL := os.Getenv("TEMPEST_APP_URL")
+	if defaultURL == "" {
+		defaultURL = "http://localhost:8080"
+	}
+
+	url := fs.String("url", defaultURL, "`URL` of the running app, defaults to $TEMPEST_APP_URL")
+	command := fs.String("exec", "", "`command` that starts the app on $PORT, stopped when the command of tempest-app ends")
+	timeout := fs.Duration("start-timeout", 30*time.Second, "maximum `duration` to wait for the app started with -exec to serve")
+	project := fs.String("project", "local", "`name` of the Tempest Project sent with the requests")
+	fs.Var(&envFlag{vars: c.environment, typ: app.ENVIRONMENT_VARIABLE_TYPE_VAR}, "env", "environment variable `KEY=VALUE` sent with the requests, can be repeated")
+	fs.Var(&envFlag{vars: c.environment, typ: app.ENVIRONMENT_VARIABLE_TYPE_SECRET}, "secret", "secret environment variable `KEY=VALUE` sent with the requests, can be repeated")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usagef("no command")
+	}
+
+	cmd, ok := findCommand(fs.Arg(0))
+	if !ok {
+		return usagef("unknown command %q, run tempest-app -h for the list of commands", fs.Arg(0))
+	}
+
+	c.command = cmd
+	c.metadata = &app.Metadata{
+		ProjectID:   "local-" + *project,
+		ProjectName: *project,
+		Author: app.Owner{
+			Name: os.Getenv("USER"),
+			Type: app.OwnerTypeUser,
+		},
+	}
+
+	if *command != "" {
+		started, stop, err := startApp(ctx, *command, *timeout, stderr)
+		if err != nil {
+			return err
+		}
+		defer stop()
+
+		*url = started
+	}
+
+	c.client = app.NewClient(http.DefaultClient, strings.TrimSuffix(*url, "/"))
+
+	return cmd.run(ctx, c, fs.Args()[1:])
+}
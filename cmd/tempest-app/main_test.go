@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+	"github.com/tempestdx/sdk-go/app"
+	"github.com/tempestdx/sdk-go/examples/fakecloud"
+)
+
+// serveEnv makes the test binary serve the fakecloud app on $PORT, so that it can be started with -exec.
+const serveEnv = "TEMPEST_APP_TEST_SERVE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(serveEnv) == "1" {
+		_ = http.ListenAndServe("127.0.0.1:"+os.Getenv("PORT"), newHandler())
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
+
+func newHandler() http.Handler {
+	a := app.New(app.WithResourceDefinition(fakecloud.ResourceDefinition(fakecloud.New(fakecloud.WithPageSize(1)))))
+
+	mux := http.NewServeMux()
+	mux.Handle(appv1connect.NewAppServiceHandler(a))
+
+	return mux
+}
+
+func runCLI(t *testing.T, stdin string, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return stdout.String(), stderr.String(), err
+}
+
+func TestCommands(t *testing.T) {
+	srv := httptest.NewServer(newHandler())
+	t.Cleanup(srv.Close)
+
+	inputFile := filepath.Join(t.TempDir(), "update.json")
+	require.NoError(t, os.WriteFile(inputFile, []byte(`{"value": "goodbye", "tags": ["cli"]}`), 0o600))
+
+	// The commands run in order against the same app.
+	testCases := []struct {
+		desc  string
+		args  []string
+		stdin string
+		check func(t *testing.T, stdout string)
+	}{
+		{
+			desc: "OK - Describe",
+			args: []string{"describe", fakecloud.ResourceType},
+			check: func(t *testing.T, stdout string) {
+				assert.Contains(t, stdout, "fakecloud_object (Fake Cloud Object)\n")
+				assert.Contains(t, stdout, "Operations:      create, read, update, delete, list, health check\n")
+				assert.Contains(t, stdout, "\n  Create input schema:\n    {\n")
+				assert.Contains(t, stdout, "\n  Action copy (Copy)\n")
+				assert.Contains(t, stdout, "\n  Instructions:\n    ## Using {{ resource.name }}\n")
+			},
+		},
+		{
+			desc: "OK - Create",
+			args: []string{"create", fakecloud.ResourceType, "-input", `{"name": "greeting", "value": "hello"}`},
+			check: func(t *testing.T, stdout string) {
+				r := decode[map[string]any](t, stdout)
+				assert.Equal(t, "obj-000001", r["external_id"])
+				assert.Equal(t, fakecloud.ResourceType, r["type"])
+				assert.Equal(t, "hello", r["properties"].(map[string]any)["value"])
+				assert.Contains(t, r["links"], map[string]any{
+					"url":   "https://fakecloud.example.com/objects/obj-000001",
+					"title": "greeting in Fake Cloud",
+					"type":  "external",
+				})
+			},
+		},
+		{
+			desc: "OK - Update From File",
+			args: []string{"update", "-input-file", inputFile, fakecloud.ResourceType, "obj-000001"},
+			check: func(t *testing.T, stdout string) {
+				r := decode[map[string]any](t, stdout)
+				assert.Equal(t, "goodbye", r["properties"].(map[string]any)["value"])
+				assert.Equal(t, []any{"cli"}, r["properties"].(map[string]any)["tags"])
+			},
+		},
+		{
+			desc:  "OK - Action From Stdin",
+			args:  []string{"action", fakecloud.ResourceType, "obj-000001", "copy", "-input-file", "-"},
+			stdin: `{"name": "greeting-copy"}`,
+			check: func(t *testing.T, stdout string) {
+				assert.JSONEq(t, `{"external_id": "obj-000002", "name": "greeting-copy"}`, stdout)
+			},
+		},
+		{
+			desc: "OK - List",
+			args: []string{"list", fakecloud.ResourceType},
+			check: func(t *testing.T, stdout string) {
+				assert.Len(t, decode[[]any](t, stdout), 2)
+			},
+		},
+		{
+			desc: "OK - List Page",
+			args: []string{"list", "-page", fakecloud.ResourceType},
+			check: func(t *testing.T, stdout string) {
+				page := decode[map[string]any](t, stdout)
+				assert.Len(t, page["resources"], 1)
+				assert.Equal(t, "obj-000002", page["next"])
+			},
+		},
+		{
+			desc: "OK - Delete",
+			args: []string{"delete", fakecloud.ResourceType, "obj-000002"},
+			check: func(t *testing.T, stdout string) {
+				assert.Equal(t, "obj-000002", decode[map[string]any](t, stdout)["external_id"])
+			},
+		},
+		{
+			desc: "OK - Health",
+			args: []string{"health", fakecloud.ResourceType},
+			check: func(t *testing.T, stdout string) {
+				assert.JSONEq(t, `{"status": "healthy", "message": ""}`, stdout)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			stdout, _, err := runCLI(t, tc.stdin, append([]string{"-url", srv.URL + "/"}, tc.args...)...)
+			require.NoError(t, err)
+
+			tc.check(t, stdout)
+		})
+	}
+}
+
+func TestDocs(t *testing.T) {
+	srv := httptest.NewServer(newHandler())
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		desc  string
+		args  []string
+		files []string
+	}{
+		{
+			desc:  "OK - Markdown",
+			files: []string{"fakecloud_object.md", "index.md"},
+		},
+		{
+			desc:  "OK - HTML",
+			args:  []string{"-html", "-title", "Fake Cloud"},
+			files: []string{"fakecloud_object.html", "index.html"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			_, _, err := runCLI(t, "", append([]string{"-url", srv.URL, "docs", dir}, tc.args...)...)
+			require.NoError(t, err)
+
+			for _, f := range tc.files {
+				assert.FileExists(t, filepath.Join(dir, f))
+			}
+		})
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	srv := httptest.NewServer(newHandler())
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		desc  string
+		args  []string
+		err   string
+		usage bool
+	}{
+		{
+			desc:  "ERR - No Command",
+			args:  []string{},
+			err:   "no command",
+			usage: true,
+		},
+		{
+			desc:  "ERR - Unknown Command",
+			args:  []string{"get"},
+			err:   `unknown command "get"`,
+			usage: true,
+		},
+		{
+			desc:  "ERR - Missing Argument",
+			args:  []string{"read", fakecloud.ResourceType},
+			err:   "usage: tempest-app read <type> <id>",
+			usage: true,
+		},
+		{
+			desc:  "ERR - Both Input Flags",
+			args:  []string{"create", fakecloud.ResourceType, "-input", "{}", "-input-file", "input.json"},
+			err:   "-input and -input-file are mutually exclusive",
+			usage: true,
+		},
+		{
+			desc:  "ERR - Invalid Environment",
+			args:  []string{"-env", "TOKEN", "describe"},
+			err:   `"TOKEN" is not KEY=VALUE`,
+			usage: true,
+		},
+		{
+			desc: "ERR - Invalid JSON",
+			args: []string{"create", fakecloud.ResourceType, "-input", `["a"]`},
+			err:  "-input is not a JSON object",
+		},
+		{
+			desc: "ERR - Invalid Input",
+			args: []string{"create", fakecloud.ResourceType, "-input", `{"value": "hello"}`},
+			err:  "invalid_argument",
+		},
+		{
+			desc: "ERR - Not Found",
+			args: []string{"read", fakecloud.ResourceType, "obj-000042"},
+			err:  "not_found",
+		},
+		{
+			desc: "ERR - Unknown Resource Type",
+			args: []string{"describe", "unknown"},
+			err:  `the app has no resource type "unknown"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, err := runCLI(t, "", append([]string{"-url", srv.URL}, tc.args...)...)
+			require.ErrorContains(t, err, tc.err)
+
+			var usage usageError
+			assert.Equal(t, tc.usage, errors.As(err, &usage))
+		})
+	}
+}
+
+func TestExec(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	t.Run("OK - Started", func(t *testing.T) {
+		t.Setenv(serveEnv, "1")
+
+		stdout, _, err := runCLI(t, "", "-exec", exe, "create", fakecloud.ResourceType, "-input", `{"name": "started"}`)
+		require.NoError(t, err)
+		assert.Equal(t, "obj-000001", decode[map[string]any](t, stdout)["external_id"])
+	})
+
+	t.Run("ERR - Exited", func(t *testing.T) {
+		t.Setenv(serveEnv, "")
+
+		_, _, err := runCLI(t, "", "-exec", exe+" -test.run=^$", "health", fakecloud.ResourceType)
+		assert.ErrorContains(t, err, "the app exited before serving")
+	})
+}
+
+func decode[T any](t *testing.T, s string) T {
+	var v T
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+
+	return v
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// printJSON prints v as indented JSON, without escaping HTML characters.
+func printJSON(w io.Writer, v any) error {
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	return e.Encode(v)
+}
+
+type linkOutput struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+type resourceOutput struct {
+	ExternalID    string         `json:"external_id"`
+	DisplayName   string         `json:"display_name,omitempty"`
+	Type          string         `json:"type"`
+	Links         []linkOutput   `json:"links,omitempty"`
+	Properties    map[string]any `json:"properties"`
+	SchemaVersion int            `json:"schema_version,omitempty"`
+}
+
+func resourceJSON(r *app.Resource) *resourceOutput {
+	if r == nil {
+		return nil
+	}
+
+	out := &resourceOutput{
+		ExternalID:    r.ExternalID,
+		DisplayName:   r.DisplayName,
+		Type:          r.Type,
+		Properties:    r.Properties,
+		SchemaVersion: r.SchemaVersion,
+	}
+	for _, l := range r.Links {
+		out.Links = append(out.Links, linkOutput{URL: l.URL, Title: l.Title, Type: l.Type.String()})
+	}
+
+	return out
+}
+
+func resourcesJSON(resources []*app.Resource) []*resourceOutput {
+	out := make([]*resourceOutput, 0, len(resources))
+	for _, r := range resources {
+		out = append(out, resourceJSON(r))
+	}
+
+	return out
+}
+
+// printDescriptor prints a resource definition for humans, with its schemas as indented JSON.
+func printDescriptor(w io.Writer, d *app.ResourceDescriptor) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s (%s)\n", d.Type, d.DisplayName)
+	if d.Description != "" {
+		fmt.Fprintf(&b, "  %s\n", d.Description)
+	}
+
+	fmt.Fprintf(&b, "\n  Lifecycle stage: %s\n", d.LifecycleStage)
+	fmt.Fprintf(&b, "  Operations:      %s\n", strings.Join(operations(d), ", "))
+
+	if len(d.Links) > 0 {
+		fmt.Fprintf(&b, "  Links:\n")
+		for _, l := range d.Links {
+			fmt.Fprintf(&b, "    %s (%s): %s\n", l.Title, l.Type, l.URL)
+		}
+	}
+
+	schemas := []struct {
+		name   string
+		schema *app.JSONSchema
+	}{
+		{"Properties schema", d.PropertiesSchema},
+		{"Create input schema", d.CreateInputSchema},
+		{"Update input schema", d.UpdateInputSchema},
+	}
+	for _, s := range schemas {
+		if err := printSchema(&b, "  ", s.name, s.schema); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range d.Actions {
+		fmt.Fprintf(&b, "\n  Action %s (%s)\n", a.Name, a.DisplayName)
+		if a.Description != "" {
+			fmt.Fprintf(&b, "    %s\n", a.Description)
+		}
+		if err := printSchema(&b, "    ", "Input schema", a.InputSchema); err != nil {
+			return err
+		}
+		if err := printSchema(&b, "    ", "Output schema", a.OutputSchema); err != nil {
+			return err
+		}
+	}
+
+	if d.InstructionsMarkdown != "" {
+		fmt.Fprintf(&b, "\n  Instructions:\n%s\n", indent(d.InstructionsMarkdown, "    "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// operations returns the names of the operations supported by the resource definition.
+func operations(d *app.ResourceDescriptor) []string {
+	supported := []struct {
+		name string
+		ok   bool
+	}{
+		{"create", d.CreateSupported},
+		{"read", d.ReadSupported},
+		{"update", d.UpdateSupported},
+		{"delete", d.DeleteSupported},
+		{"list", d.ListSupported},
+		{"health check", d.HealthCheckSupported},
+	}
+
+	var ops []string
+	for _, s := range supported {
+		if s.ok {
+			ops = append(ops, s.name)
+		}
+	}
+	if len(ops) == 0 {
+		ops = append(ops, "none")
+	}
+
+	return ops
+}
+
+func printSchema(b *strings.Builder, prefix, name string, s *app.JSONSchema) error {
+	if s == nil {
+		return nil
+	}
+
+	raw, err := s.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", strings.ToLower(name), err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, "", "  "); err != nil {
+		return fmt.Errorf("indent %s: %w", strings.ToLower(name), err)
+	}
+
+	fmt.Fprintf(b, "\n%s%s:\n%s\n", prefix, name, indent(indented.String(), prefix+"  "))
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// stopTimeout is how long a started app has to exit after being interrupted, before it is killed.
+const stopTimeout = 5 * time.Second
+
+// startApp runs the command, split on spaces, with PORT set to a free port, and waits until the app
+// it starts answers Describe on that port. It returns the URL of the app and a function that stops it.
+// The output of the app is written to w, so that the output of the commands stays JSON.
+func startApp(ctx context.Context, command string, timeout time.Duration, w io.Writer) (string, func(), error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", nil, usagef("-exec is empty")
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return "", nil, fmt.Errorf("find a free port: %w", err)
+	}
+	url := "http://127.0.0.1:" + port
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "PORT="+port)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return "", nil, fmt.Errorf("start %s: %w", args[0], err)
+	}
+
+	// exited is closed when the command exits, after exitErr is set.
+	var exitErr error
+	exited := make(chan struct{})
+	go func() {
+		exitErr = cmd.Wait()
+		close(exited)
+	}()
+
+	stop := func() {
+		select {
+		case <-exited:
+			return
+		default:
+		}
+
+		interrupt(cmd)
+		select {
+		case <-exited:
+		case <-time.After(stopTimeout):
+			kill(cmd)
+			<-exited
+		}
+	}
+
+	if err := waitReady(ctx, url, timeout, exited); err != nil {
+		stop()
+		if errors.Is(err, errExited) {
+			if exitErr == nil {
+				exitErr = errors.New("exit status 0")
+			}
+			err = fmt.Errorf("%w on %s: %w", errExited, url, exitErr)
+		}
+		return "", nil, err
+	}
+
+	return url, stop, nil
+}
+
+var errExited = errors.New("the app exited before serving")
+
+// waitReady polls the app until it answers Describe. It returns errExited if exited is closed first.
+func waitReady(ctx context.Context, url string, timeout time.Duration, exited <-chan struct{}) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := app.NewClient(http.DefaultClient, url)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		attempt, cancelAttempt := context.WithTimeout(ctx, time.Second)
+		_, err := client.Describe(attempt)
+		cancelAttempt()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-exited:
+			return errExited
+		case <-ctx.Done():
+			return fmt.Errorf("the app did not serve on %s within %s: %w", url, timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	return port, err
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package main
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(*exec.Cmd) {}
+
+// interrupt kills the command, as interrupts can't be sent to processes on every platform.
+func interrupt(cmd *exec.Cmd) {
+	kill(cmd)
+}
+
+func kill(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
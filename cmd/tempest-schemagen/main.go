@@ -0,0 +1,74 @@
+// Command tempest-schemagen generates Go types from a JSON schema of a Tempest app.
+// It is meant to be run by go generate, which sets the package of the generated file:
+//
+//	//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type CreateInput -o create_input_gen.go schema/create_input.json
+//
+// Usage:
+//
+//	tempest-schemagen [-type name] [-package name] [-o file] <schema>
+//
+// The generated file is written to -o, or to stdout. See the schemagen package for the generated code.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tempestdx/sdk-go/schemagen"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "tempest-schemagen: %v\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tempest-schemagen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tempest-schemagen [-type name] [-package name] [-o file] <schema>")
+		fs.PrintDefaults()
+	}
+
+	typeName := fs.String("type", "", "`name` of the generated struct, defaults to the title of the schema")
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "`name` of the package of the generated file, defaults to $GOPACKAGE")
+	output := fs.String("o", "", "`file` to write the generated code to, defaults to stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a single schema file")
+	}
+
+	path := fs.Arg(0)
+	schema, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+
+	src, err := schemagen.Generate(schema, schemagen.Options{
+		Package:  *pkg,
+		TypeName: *typeName,
+		Source:   filepath.ToSlash(path),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if *output == "" {
+		_, err := stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(*output, src, 0o644)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "input.json")
+	require.NoError(t, os.WriteFile(schema, []byte(`{"title": "input", "type": "object", "properties": {"name": {"type": "string"}}}`), 0o600))
+
+	t.Setenv("GOPACKAGE", "buckets")
+
+	testCases := []struct {
+		desc string
+		args []string
+		want string
+		err  string
+	}{
+		{
+			desc: "OK - Stdout",
+			args: []string{schema},
+			want: "package buckets",
+		},
+		{
+			desc: "OK - Type And Package",
+			args: []string{"-type", "BucketInput", "-package", "storage", schema},
+			want: "type BucketInput struct",
+		},
+		{
+			desc: "ERR - No Schema",
+			args: []string{},
+			err:  "expected a single schema file",
+		},
+		{
+			desc: "ERR - Missing Schema",
+			args: []string{filepath.Join(dir, "missing.json")},
+			err:  "read schema",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(tc.args, &stdout, &stderr)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, stdout.String(), tc.want)
+		})
+	}
+
+	t.Run("OK - Output File", func(t *testing.T) {
+		out := filepath.Join(dir, "input_gen.go")
+		require.NoError(t, run([]string{"-o", out, schema}, &bytes.Buffer{}, &bytes.Buffer{}))
+
+		b, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "func InputFromMap(m map[string]any) (*Input, error)")
+	})
+}
@@ -0,0 +1,315 @@
+// Package fakecloud is an in-memory key/value "cloud" and a ResourceDefinition that manages its objects.
+//
+// It is a runnable reference for implementing a Tempest app, with every operation, a paginated List,
+// an action, a health check, link templates and instructions. The Cloud can be configured with latency,
+// random and scripted failures, and a page size, which makes it a test double for code that wraps
+// handlers or calls apps, such as middleware.
+//
+//	cloud := fakecloud.New(fakecloud.WithLatency(50 * time.Millisecond))
+//	a := app.New(app.WithResourceDefinition(fakecloud.ResourceDefinition(cloud)))
+package fakecloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+var (
+	// ErrNotFound is returned for objects that don't exist. It wraps app.ErrNotFound,
+	// so that handlers can return it as-is.
+	ErrNotFound = fmt.Errorf("fakecloud: object %w", app.ErrNotFound)
+	// ErrConflict is returned when creating an object with the name of an existing object.
+	ErrConflict = errors.New("fakecloud: an object with this name already exists")
+	// ErrInjected is returned by the calls that fail because of WithFailureRate.
+	ErrInjected = errors.New("fakecloud: injected failure")
+	// ErrUnavailable is returned by every call while the Cloud is unavailable. See SetAvailable.
+	ErrUnavailable = errors.New("fakecloud: service unavailable")
+)
+
+// Object is a value stored in the Cloud.
+type Object struct {
+	ID        string
+	Name      string
+	Value     string
+	Tags      []string
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (o *Object) clone() *Object {
+	c := *o
+	c.Tags = slices.Clone(o.Tags)
+	return &c
+}
+
+// Cloud is an in-memory key/value store. It is safe for concurrent use.
+type Cloud struct {
+	mu       sync.Mutex
+	objects  map[string]*Object
+	nextID   int
+	failures []error
+	down     bool
+
+	latency     time.Duration
+	failureRate float64
+	rand        *rand.Rand
+	pageSize    int
+	now         func() time.Time
+}
+
+// Option configures a Cloud.
+type Option func(*Cloud)
+
+// WithLatency delays every call by d, or until the context is done.
+func WithLatency(d time.Duration) Option {
+	return func(c *Cloud) {
+		c.latency = d
+	}
+}
+
+// WithFailureRate fails the given fraction of the calls, between 0 and 1, with ErrInjected.
+// The calls that fail are picked by a random generator with the seed, so a sequence of calls
+// always fails the same way.
+func WithFailureRate(rate float64, seed uint64) Option {
+	return func(c *Cloud) {
+		c.failureRate = rate
+		c.rand = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// WithPageSize sets the maximum number of objects returned by List. Defaults to 50.
+func WithPageSize(n int) Option {
+	return func(c *Cloud) {
+		c.pageSize = n
+	}
+}
+
+// WithClock sets the function returning the time of creation and update of the objects.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cloud) {
+		c.now = now
+	}
+}
+
+// New returns an empty Cloud.
+func New(opts ...Option) *Cloud {
+	c := &Cloud{
+		objects:  make(map[string]*Object),
+		pageSize: 50,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// FailNext makes the next call fail with err. Errors queued by successive calls are
+// returned by successive calls, before any other failure.
+func (c *Cloud) FailNext(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures = append(c.failures, err)
+}
+
+// SetAvailable sets whether the Cloud is available. While it is not, every call fails with ErrUnavailable.
+func (c *Cloud) SetAvailable(available bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.down = !available
+}
+
+// call simulates the latency and failures of a request. The lock must not be held.
+func (c *Cloud) call(ctx context.Context) error {
+	if c.latency > 0 {
+		t := time.NewTimer(c.latency)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.down {
+		return ErrUnavailable
+	}
+
+	if len(c.failures) > 0 {
+		err := c.failures[0]
+		c.failures = c.failures[1:]
+		return err
+	}
+
+	if c.rand != nil && c.rand.Float64() < c.failureRate {
+		return ErrInjected
+	}
+
+	return nil
+}
+
+// Ping checks that the Cloud is reachable.
+func (c *Cloud) Ping(ctx context.Context) error {
+	return c.call(ctx)
+}
+
+// Create stores a new object.
+func (c *Cloud) Create(ctx context.Context, name, value string, tags []string) (*Object, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.create(name, value, tags)
+}
+
+func (c *Cloud) create(name, value string, tags []string) (*Object, error) {
+	for _, o := range c.objects {
+		if o.Name == name {
+			return nil, ErrConflict
+		}
+	}
+
+	c.nextID++
+	now := c.now()
+	o := &Object{
+		ID:        fmt.Sprintf("obj-%06d", c.nextID),
+		Name:      name,
+		Value:     value,
+		Tags:      slices.Clone(tags),
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.objects[o.ID] = o
+
+	return o.clone(), nil
+}
+
+// Get returns the object with the ID.
+func (c *Cloud) Get(ctx context.Context, id string) (*Object, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.objects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return o.clone(), nil
+}
+
+// Update replaces the value and tags of the object with the ID, when they are not nil,
+// and increments its version.
+func (c *Cloud) Update(ctx context.Context, id string, value *string, tags []string) (*Object, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.objects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if value != nil {
+		o.Value = *value
+	}
+	if tags != nil {
+		o.Tags = slices.Clone(tags)
+	}
+	o.Version++
+	o.UpdatedAt = c.now()
+
+	return o.clone(), nil
+}
+
+// Delete removes the object with the ID and returns it.
+func (c *Cloud) Delete(ctx context.Context, id string) (*Object, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.objects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(c.objects, id)
+
+	return o, nil
+}
+
+// Copy stores a copy of the object with the ID under a new name.
+func (c *Cloud) Copy(ctx context.Context, id, name string) (*Object, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.objects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return c.create(name, o.Value, o.Tags)
+}
+
+// List returns a page of objects ordered by ID, starting at the page token.
+// It returns the token of the next page, which is empty on the last page.
+func (c *Cloud) List(ctx context.Context, token string) ([]*Object, string, error) {
+	if err := c.call(ctx); err != nil {
+		return nil, "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := slices.Sorted(maps.Keys(c.objects))
+
+	// The token is the ID of the first object of the page, so that objects deleted
+	// between two pages don't shift the following ones.
+	start, _ := slices.BinarySearch(ids, token)
+	end := min(start+c.pageSize, len(ids))
+
+	objects := make([]*Object, 0, end-start)
+	for _, id := range ids[start:end] {
+		objects = append(objects, c.objects[id].clone())
+	}
+
+	var next string
+	if end < len(ids) {
+		next = ids[end]
+	}
+
+	return objects, next, nil
+}
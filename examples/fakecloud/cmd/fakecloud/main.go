@@ -0,0 +1,30 @@
+// Command fakecloud serves an app with the fakecloud resource definition on the port of the PORT
+// environment variable, defaulting to 8080. It can be started and invoked with tempest-app:
+//
+//	go run ./cmd/tempest-app -exec "go run ./examples/fakecloud/cmd/fakecloud" describe
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/tempestdx/protobuf/gen/go/tempestdx/app/v1/appv1connect"
+	"github.com/tempestdx/sdk-go/app"
+	"github.com/tempestdx/sdk-go/examples/fakecloud"
+)
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	a := app.New(app.WithResourceDefinition(fakecloud.ResourceDefinition(fakecloud.New())))
+
+	mux := http.NewServeMux()
+	mux.Handle(appv1connect.NewAppServiceHandler(a))
+
+	log.Printf("serving the fakecloud app on :%s", port)
+	log.Fatal(http.ListenAndServe(":"+port, mux))
+}
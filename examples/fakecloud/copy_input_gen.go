@@ -0,0 +1,44 @@
+// Code generated by tempest-schemagen from schema/copy_input.json. DO NOT EDIT.
+
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CopyInput is generated from schema/copy_input.json.
+type CopyInput struct {
+	// The name of the copy.
+	Name string `json:"name"`
+}
+
+// NewCopyInput returns a CopyInput with the default values of the schema.
+func NewCopyInput() *CopyInput {
+	return &CopyInput{}
+}
+
+// CopyInputFromMap converts a map, such as an input or Resource.Properties, to a CopyInput.
+// Properties that are not in the schema are ignored, and omitted properties have their default values.
+func CopyInputFromMap(m map[string]any) (*CopyInput, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal CopyInput: %w", err)
+	}
+
+	v := NewCopyInput()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unmarshal CopyInput: %w", err)
+	}
+
+	return v, nil
+}
+
+// ToMap converts the CopyInput to a map of JSON values, such as Resource.Properties or an action output.
+// Nil pointers and slices are omitted.
+func (c *CopyInput) ToMap() map[string]any {
+	m := make(map[string]any)
+	m["name"] = c.Name
+
+	return m
+}
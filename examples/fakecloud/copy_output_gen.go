@@ -0,0 +1,47 @@
+// Code generated by tempest-schemagen from schema/copy_output.json. DO NOT EDIT.
+
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CopyOutput is generated from schema/copy_output.json.
+type CopyOutput struct {
+	// The ExternalID of the copy.
+	ExternalID string `json:"external_id"`
+
+	Name string `json:"name"`
+}
+
+// NewCopyOutput returns a CopyOutput with the default values of the schema.
+func NewCopyOutput() *CopyOutput {
+	return &CopyOutput{}
+}
+
+// CopyOutputFromMap converts a map, such as an input or Resource.Properties, to a CopyOutput.
+// Properties that are not in the schema are ignored, and omitted properties have their default values.
+func CopyOutputFromMap(m map[string]any) (*CopyOutput, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal CopyOutput: %w", err)
+	}
+
+	v := NewCopyOutput()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unmarshal CopyOutput: %w", err)
+	}
+
+	return v, nil
+}
+
+// ToMap converts the CopyOutput to a map of JSON values, such as Resource.Properties or an action output.
+// Nil pointers and slices are omitted.
+func (c *CopyOutput) ToMap() map[string]any {
+	m := make(map[string]any)
+	m["external_id"] = c.ExternalID
+	m["name"] = c.Name
+
+	return m
+}
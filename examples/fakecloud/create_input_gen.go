@@ -0,0 +1,61 @@
+// Code generated by tempest-schemagen from schema/create_input.json. DO NOT EDIT.
+
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CreateInput is generated from schema/create_input.json.
+type CreateInput struct {
+	// The unique name of the object.
+	Name string `json:"name"`
+
+	// Tags to attach to the object.
+	Tags []string `json:"tags,omitempty"`
+
+	// The value to store in the object.
+	Value string `json:"value"`
+}
+
+// NewCreateInput returns a CreateInput with the default values of the schema.
+func NewCreateInput() *CreateInput {
+	c := &CreateInput{}
+	c.Value = ""
+
+	return c
+}
+
+// CreateInputFromMap converts a map, such as an input or Resource.Properties, to a CreateInput.
+// Properties that are not in the schema are ignored, and omitted properties have their default values.
+func CreateInputFromMap(m map[string]any) (*CreateInput, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal CreateInput: %w", err)
+	}
+
+	v := NewCreateInput()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unmarshal CreateInput: %w", err)
+	}
+
+	return v, nil
+}
+
+// ToMap converts the CreateInput to a map of JSON values, such as Resource.Properties or an action output.
+// Nil pointers and slices are omitted.
+func (c *CreateInput) ToMap() map[string]any {
+	m := make(map[string]any)
+	m["name"] = c.Name
+	if c.Tags != nil {
+		s := make([]any, 0, len(c.Tags))
+		for _, item := range c.Tags {
+			s = append(s, item)
+		}
+		m["tags"] = s
+	}
+	m["value"] = c.Value
+
+	return m
+}
@@ -0,0 +1,216 @@
+package fakecloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tempestdx/sdk-go/app"
+	"github.com/tempestdx/sdk-go/apptest"
+)
+
+func TestConformance(t *testing.T) {
+	a := app.New(app.WithResourceDefinition(ResourceDefinition(New(WithPageSize(1)))))
+
+	apptest.RunConformance(t, a, apptest.ConformanceOptions{
+		CreateInput: map[string]any{"name": "greeting", "value": "hello"},
+		UpdateInput: map[string]any{"value": "goodbye", "tags": []any{"test"}},
+	})
+}
+
+func TestResourceDefinition(t *testing.T) {
+	cloud := New(WithPageSize(2))
+	h := apptest.New(t, app.New(app.WithResourceDefinition(ResourceDefinition(cloud))))
+
+	created, err := h.Create(ResourceType, map[string]any{"name": "greeting", "value": "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "obj-000001", created.ExternalID)
+	assert.Equal(t, float64(1), created.Properties["version"])
+	assert.Contains(t, created.Links, &app.Link{
+		URL:   "https://fakecloud.example.com/objects/obj-000001",
+		Title: "greeting in Fake Cloud",
+		Type:  app.LinkTypeExternal,
+	})
+
+	out, err := h.Action(created, "copy", map[string]any{"name": "greeting-copy"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"external_id": "obj-000002", "name": "greeting-copy"}, out)
+
+	_, err = h.Action(created, "copy", map[string]any{"name": "Not A Name"})
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	for i := range 3 {
+		_, err := h.Create(ResourceType, map[string]any{"name": fmt.Sprintf("object-%d", i)})
+		require.NoError(t, err)
+	}
+
+	page, next, err := h.ListPage(ResourceType, "")
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "obj-000003", next)
+
+	all, err := h.List(ResourceType)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	cloud.FailNext(errors.New("boom"))
+	_, err = h.Read(created)
+	assert.Equal(t, connect.CodeInternal, connect.CodeOf(err))
+	assert.ErrorContains(t, err, "boom")
+
+	health, err := h.Health(ResourceType)
+	require.NoError(t, err)
+	assert.Equal(t, app.HealthCheckStatusHealthy, health.Status)
+
+	cloud.SetAvailable(false)
+	health, err = h.Health(ResourceType)
+	require.NoError(t, err)
+	assert.Equal(t, &app.HealthCheckResponse{Status: app.HealthCheckStatusDisrupted, Message: ErrUnavailable.Error()}, health)
+}
+
+func TestCloud(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc string
+		opts []Option
+		run  func(t *testing.T, c *Cloud)
+	}{
+		{
+			desc: "OK - Clock",
+			opts: []Option{WithClock(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) })},
+			run: func(t *testing.T, c *Cloud) {
+				o, err := c.Create(ctx, "a", "value", nil)
+				require.NoError(t, err)
+				assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), o.CreatedAt)
+			},
+		},
+		{
+			desc: "OK - Update",
+			run: func(t *testing.T, c *Cloud) {
+				o, err := c.Create(ctx, "a", "value", []string{"test"})
+				require.NoError(t, err)
+
+				o, err = c.Update(ctx, o.ID, nil, nil)
+				require.NoError(t, err)
+				assert.Equal(t, 2, o.Version)
+				assert.Equal(t, "value", o.Value)
+				assert.Equal(t, []string{"test"}, o.Tags)
+			},
+		},
+		{
+			desc: "OK - List Skips Deleted Objects",
+			opts: []Option{WithPageSize(1)},
+			run: func(t *testing.T, c *Cloud) {
+				for _, name := range []string{"a", "b", "c"} {
+					_, err := c.Create(ctx, name, "", nil)
+					require.NoError(t, err)
+				}
+
+				_, next, err := c.List(ctx, "")
+				require.NoError(t, err)
+				assert.Equal(t, "obj-000002", next)
+
+				_, err = c.Delete(ctx, "obj-000002")
+				require.NoError(t, err)
+
+				objects, next, err := c.List(ctx, next)
+				require.NoError(t, err)
+				assert.Equal(t, "obj-000003", objects[0].ID)
+				assert.Empty(t, next)
+			},
+		},
+		{
+			desc: "OK - Failure Rate Is Deterministic",
+			run: func(t *testing.T, _ *Cloud) {
+				failures := func() []bool {
+					c := New(WithFailureRate(0.5, 42))
+					var failed []bool
+					for range 20 {
+						failed = append(failed, errors.Is(c.Ping(ctx), ErrInjected))
+					}
+					return failed
+				}
+
+				first := failures()
+				assert.Contains(t, first, true)
+				assert.Contains(t, first, false)
+				assert.Equal(t, first, failures())
+			},
+		},
+		{
+			desc: "ERR - Not Found",
+			run: func(t *testing.T, c *Cloud) {
+				_, err := c.Get(ctx, "obj-000001")
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.ErrorIs(t, err, app.ErrNotFound)
+			},
+		},
+		{
+			desc: "ERR - Conflict",
+			run: func(t *testing.T, c *Cloud) {
+				_, err := c.Create(ctx, "a", "", nil)
+				require.NoError(t, err)
+
+				_, err = c.Create(ctx, "a", "", nil)
+				assert.ErrorIs(t, err, ErrConflict)
+			},
+		},
+		{
+			desc: "ERR - Fail Next",
+			run: func(t *testing.T, c *Cloud) {
+				first, second := errors.New("first"), errors.New("second")
+				c.FailNext(first)
+				c.FailNext(second)
+
+				assert.ErrorIs(t, c.Ping(ctx), first)
+				assert.ErrorIs(t, c.Ping(ctx), second)
+				assert.NoError(t, c.Ping(ctx))
+			},
+		},
+		{
+			desc: "ERR - Latency Canceled",
+			opts: []Option{WithLatency(time.Hour)},
+			run: func(t *testing.T, c *Cloud) {
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				defer cancel()
+
+				assert.ErrorIs(t, c.Ping(ctx), context.DeadlineExceeded)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.run(t, New(tc.opts...))
+		})
+	}
+}
+
+func TestGeneratedTypes(t *testing.T) {
+	props := &ObjectProperties{
+		Name:      "greeting",
+		Tags:      []string{"a", "b"},
+		Version:   2,
+		CreatedAt: "2024-01-02T03:04:05Z",
+	}
+
+	m := props.ToMap()
+	assert.Equal(t, []any{"a", "b"}, m["tags"])
+	assert.Equal(t, float64(2), m["version"])
+
+	decoded, err := ObjectPropertiesFromMap(m)
+	require.NoError(t, err)
+	assert.Equal(t, props, decoded)
+
+	in, err := UpdateInputFromMap(map[string]any{"tags": []any{"a"}})
+	require.NoError(t, err)
+	assert.Nil(t, in.Value, "absent properties are nil")
+
+	_, err = CreateInputFromMap(map[string]any{"name": 42.0})
+	assert.ErrorContains(t, err, "unmarshal CreateInput")
+}
@@ -0,0 +1,68 @@
+// Code generated by tempest-schemagen from schema/properties.json. DO NOT EDIT.
+
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ObjectProperties is generated from schema/properties.json.
+type ObjectProperties struct {
+	CreatedAt string `json:"created_at"`
+
+	// The unique name of the object.
+	Name string `json:"name"`
+
+	// Tags attached to the object.
+	Tags []string `json:"tags,omitempty"`
+
+	UpdatedAt string `json:"updated_at"`
+
+	// The value stored in the object.
+	Value string `json:"value"`
+
+	// The version of the object, incremented on every update.
+	Version int `json:"version"`
+}
+
+// NewObjectProperties returns an ObjectProperties with the default values of the schema.
+func NewObjectProperties() *ObjectProperties {
+	return &ObjectProperties{}
+}
+
+// ObjectPropertiesFromMap converts a map, such as an input or Resource.Properties, to an ObjectProperties.
+// Properties that are not in the schema are ignored, and omitted properties have their default values.
+func ObjectPropertiesFromMap(m map[string]any) (*ObjectProperties, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal ObjectProperties: %w", err)
+	}
+
+	v := NewObjectProperties()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unmarshal ObjectProperties: %w", err)
+	}
+
+	return v, nil
+}
+
+// ToMap converts the ObjectProperties to a map of JSON values, such as Resource.Properties or an action output.
+// Nil pointers and slices are omitted.
+func (o *ObjectProperties) ToMap() map[string]any {
+	m := make(map[string]any)
+	m["created_at"] = o.CreatedAt
+	m["name"] = o.Name
+	if o.Tags != nil {
+		s := make([]any, 0, len(o.Tags))
+		for _, item := range o.Tags {
+			s = append(s, item)
+		}
+		m["tags"] = s
+	}
+	m["updated_at"] = o.UpdatedAt
+	m["value"] = o.Value
+	m["version"] = float64(o.Version)
+
+	return m
+}
@@ -0,0 +1,209 @@
+package fakecloud
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// ResourceType is the type of the ResourceDefinition returned by ResourceDefinition.
+const ResourceType = "fakecloud_object"
+
+//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type ObjectProperties -o properties_gen.go schema/properties.json
+//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type CreateInput -o create_input_gen.go schema/create_input.json
+//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type UpdateInput -o update_input_gen.go schema/update_input.json
+//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type CopyInput -o copy_input_gen.go schema/copy_input.json
+//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type CopyOutput -o copy_output_gen.go schema/copy_output.json
+
+var (
+	//go:embed schema/properties.json
+	propertiesSchema []byte
+	//go:embed schema/create_input.json
+	createInputSchema []byte
+	//go:embed schema/update_input.json
+	updateInputSchema []byte
+	//go:embed schema/copy_input.json
+	copyInputSchema []byte
+	//go:embed schema/copy_output.json
+	copyOutputSchema []byte
+)
+
+const instructions = `## Using {{ resource.name }}
+
+Read the value of the object with the Fake Cloud CLI:
+
+` + "```sh\nfakecloud objects get {{ resource.external_id }}\n```"
+
+// ResourceDefinition returns a ResourceDefinition that manages the objects of the Cloud.
+func ResourceDefinition(c *Cloud) app.ResourceDefinition {
+	h := handlers{cloud: c}
+
+	rd := app.ResourceDefinition{
+		Type:             ResourceType,
+		DisplayName:      "Fake Cloud Object",
+		Description:      "A key/value object stored in the in-memory Fake Cloud.",
+		PropertiesSchema: app.MustParseJSONSchema(propertiesSchema),
+		LifecycleStage:   app.LifecycleStageOperate,
+		Links: []app.Link{
+			{
+				URL:   "https://pkg.go.dev/github.com/tempestdx/sdk-go/examples/fakecloud",
+				Title: "Fake Cloud",
+				Type:  app.LinkTypeDocumentation,
+			},
+		},
+		LinkTemplates: []app.LinkTemplate{
+			{
+				URL:   "https://fakecloud.example.com/objects/{{ resource.external_id }}",
+				Title: "{{ resource.name }} in Fake Cloud",
+				Type:  app.LinkTypeExternal,
+			},
+		},
+		InstructionsMarkdown: instructions,
+	}
+
+	rd.CreateFn(h.create, app.MustParseJSONSchema(createInputSchema))
+	rd.ReadFn(h.read)
+	rd.UpdateFn(h.update, app.MustParseJSONSchema(updateInputSchema))
+	rd.DeleteFn(h.delete)
+	rd.ListFn(h.list)
+	rd.HealthCheckFn(h.healthcheck)
+	rd.AddActionDefinition(app.ActionDefinition{
+		Name:         "copy",
+		DisplayName:  "Copy",
+		Description:  "Copy the value and tags of the object to a new object.",
+		InputSchema:  app.MustParseJSONSchema(copyInputSchema),
+		OutputSchema: app.MustParseJSONSchema(copyOutputSchema),
+		Handler:      h.copy,
+	})
+
+	return rd
+}
+
+// handlers adapts the Cloud to the handler signatures of the SDK.
+type handlers struct {
+	cloud *Cloud
+}
+
+func (h handlers) create(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	in, err := CreateInputFromMap(req.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := h.cloud.Create(ctx, in.Name, in.Value, in.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("create object %s: %w", in.Name, err)
+	}
+
+	return &app.OperationResponse{Resource: resource(o)}, nil
+}
+
+func (h handlers) read(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	o, err := h.cloud.Get(ctx, req.Resource.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("get object %s: %w", req.Resource.ExternalID, err)
+	}
+
+	return &app.OperationResponse{Resource: resource(o)}, nil
+}
+
+func (h handlers) update(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	in, err := UpdateInputFromMap(req.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := h.cloud.Update(ctx, req.Resource.ExternalID, in.Value, in.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("update object %s: %w", req.Resource.ExternalID, err)
+	}
+
+	return &app.OperationResponse{Resource: resource(o)}, nil
+}
+
+func (h handlers) delete(ctx context.Context, req *app.OperationRequest) (*app.OperationResponse, error) {
+	o, err := h.cloud.Delete(ctx, req.Resource.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("delete object %s: %w", req.Resource.ExternalID, err)
+	}
+
+	return &app.OperationResponse{Resource: resource(o)}, nil
+}
+
+func (h handlers) list(ctx context.Context, req *app.ListRequest) (*app.ListResponse, error) {
+	objects, next, err := h.cloud.List(ctx, req.Next)
+	if err != nil {
+		return nil, fmt.Errorf("list objects: %w", err)
+	}
+
+	resources := make([]*app.Resource, 0, len(objects))
+	for _, o := range objects {
+		resources = append(resources, resource(o))
+	}
+
+	return &app.ListResponse{
+		Resources: resources,
+		Next:      next,
+	}, nil
+}
+
+// slowPing is the latency above which the Cloud is reported as degraded.
+const slowPing = time.Second
+
+func (h handlers) healthcheck(ctx context.Context) (*app.HealthCheckResponse, error) {
+	start := time.Now()
+	if err := h.cloud.Ping(ctx); err != nil {
+		// Errors are reported by the SDK as a disrupted health check, with the error as message.
+		return nil, err
+	}
+
+	if d := time.Since(start); d > slowPing {
+		return &app.HealthCheckResponse{
+			Status:  app.HealthCheckStatusDegraded,
+			Message: fmt.Sprintf("Fake Cloud responded in %s", d.Round(time.Millisecond)),
+		}, nil
+	}
+
+	return &app.HealthCheckResponse{Status: app.HealthCheckStatusHealthy}, nil
+}
+
+func (h handlers) copy(ctx context.Context, req *app.ActionRequest) (*app.ActionResponse, error) {
+	in, err := CopyInputFromMap(req.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := h.cloud.Copy(ctx, req.Resource.ExternalID, in.Name)
+	if err != nil {
+		return nil, fmt.Errorf("copy object %s: %w", req.Resource.ExternalID, err)
+	}
+
+	out := CopyOutput{
+		ExternalID: o.ID,
+		Name:       o.Name,
+	}
+
+	return &app.ActionResponse{Output: out.ToMap()}, nil
+}
+
+// resource converts an Object to a Resource.
+func resource(o *Object) *app.Resource {
+	properties := ObjectProperties{
+		Name:      o.Name,
+		Value:     o.Value,
+		Tags:      o.Tags,
+		Version:   o.Version,
+		CreatedAt: o.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt: o.UpdatedAt.Format(time.RFC3339Nano),
+	}
+
+	return &app.Resource{
+		ExternalID:  o.ID,
+		DisplayName: o.Name,
+		Type:        ResourceType,
+		Properties:  properties.ToMap(),
+	}
+}
@@ -0,0 +1,56 @@
+// Code generated by tempest-schemagen from schema/update_input.json. DO NOT EDIT.
+
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UpdateInput is generated from schema/update_input.json.
+type UpdateInput struct {
+	// The tags replacing the tags of the object.
+	Tags []string `json:"tags,omitempty"`
+
+	// The new value of the object.
+	Value *string `json:"value,omitempty"`
+}
+
+// NewUpdateInput returns an UpdateInput with the default values of the schema.
+func NewUpdateInput() *UpdateInput {
+	return &UpdateInput{}
+}
+
+// UpdateInputFromMap converts a map, such as an input or Resource.Properties, to an UpdateInput.
+// Properties that are not in the schema are ignored, and omitted properties have their default values.
+func UpdateInputFromMap(m map[string]any) (*UpdateInput, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal UpdateInput: %w", err)
+	}
+
+	v := NewUpdateInput()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("unmarshal UpdateInput: %w", err)
+	}
+
+	return v, nil
+}
+
+// ToMap converts the UpdateInput to a map of JSON values, such as Resource.Properties or an action output.
+// Nil pointers and slices are omitted.
+func (u *UpdateInput) ToMap() map[string]any {
+	m := make(map[string]any)
+	if u.Tags != nil {
+		s := make([]any, 0, len(u.Tags))
+		for _, item := range u.Tags {
+			s = append(s, item)
+		}
+		m["tags"] = s
+	}
+	if u.Value != nil {
+		m["value"] = *u.Value
+	}
+
+	return m
+}
@@ -8,6 +8,7 @@ require (
 	github.com/stretchr/testify v1.11.1
 	github.com/tempestdx/protobuf v0.1.4
 	github.com/tidwall/gjson v1.18.0
+	golang.org/x/text v0.24.0
 	golang.org/x/tools v0.41.0
 	google.golang.org/protobuf v1.36.11
 )
@@ -19,6 +20,5 @@ require (
 	github.com/tidwall/pretty v1.2.1 // indirect
 	golang.org/x/mod v0.32.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/text v0.24.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
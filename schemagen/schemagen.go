@@ -0,0 +1,588 @@
+// Package schemagen generates Go types from the JSON schemas of a Tempest app, so that handlers can work
+// with structs instead of map[string]any.
+//
+// Generate reads the same schema bytes as app.ParseJSONSchema and emits, for a schema of type object:
+//
+//   - a struct with a field and a JSON tag per property, in the display order of the properties
+//   - typed constants for the properties with an enum
+//   - a New constructor that sets the default values of the schema
+//   - FromMap and ToMap functions, to convert the struct from and to operation inputs, action outputs
+//     and Resource.Properties
+//
+// Required properties and properties with a default are values. Other properties are pointers, or slices,
+// which are nil when the property is absent. Integers are int, and are converted to float64 by ToMap,
+// like every number decoded from JSON.
+//
+// The tempest-schemagen command runs Generate from go:generate directives:
+//
+//	//go:generate go run github.com/tempestdx/sdk-go/cmd/tempest-schemagen -type CreateInput -o create_input_gen.go schema/create_input.json
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tempestdx/sdk-go/app"
+)
+
+// Options configures Generate.
+type Options struct {
+	// Package is the name of the package of the generated file.
+	Package string
+	// TypeName is the name of the generated struct. Defaults to the title of the schema.
+	TypeName string
+	// Source is the path of the schema, mentioned in the header and the doc comments of the generated file.
+	Source string
+	// SchemaOptions are used to parse the schema, when it uses custom formats or keywords.
+	SchemaOptions []app.SchemaOption
+}
+
+// Generate returns the formatted Go source of the types of the schema.
+func Generate(schema []byte, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, errors.New("package is required")
+	}
+
+	parsed, err := app.ParseJSONSchema(schema, opts.SchemaOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	var raw map[string]any
+	d := json.NewDecoder(bytes.NewReader(schema))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+
+	typeName := opts.TypeName
+	if typeName == "" {
+		title, _ := raw["title"].(string)
+		typeName = goName(title)
+	}
+	if typeName == "" {
+		return nil, errors.New("type name is required for schemas without a title")
+	}
+	if !isExported(typeName) {
+		return nil, fmt.Errorf("type name %q is not an exported Go identifier", typeName)
+	}
+
+	g := &generator{
+		typeName: typeName,
+		source:   opts.Source,
+	}
+
+	properties, _ := raw["properties"].(map[string]any)
+	required, _ := raw["required"].([]any)
+
+	for _, name := range parsed.PropertyOrder() {
+		p, _ := properties[name].(map[string]any)
+
+		f, err := g.field(name, p, slices.Contains(required, any(name)))
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", name, err)
+		}
+		g.fields = append(g.fields, f)
+	}
+
+	description, _ := raw["description"].(string)
+	src := g.generate(opts.Package, description)
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, src)
+	}
+
+	return formatted, nil
+}
+
+// goType is the Go type of a property, or of the items of an array property.
+type goType struct {
+	// name is the name of the Go type.
+	name string
+	// base is the Go type of the values of the JSON type: string, int, float64, bool or any.
+	base string
+	// enum holds the constants of the type, if the property has an enum.
+	enum []enumValue
+}
+
+type enumValue struct {
+	name  string
+	value any
+}
+
+// jsonValue returns the expression that converts expr, of the type, to a JSON value.
+func (t goType) jsonValue(expr string) string {
+	switch {
+	case t.base == "int":
+		return "float64(" + expr + ")"
+	case t.enum != nil:
+		return t.base + "(" + expr + ")"
+	default:
+		return expr
+	}
+}
+
+// literal returns the Go literal of a value of the type.
+func (t goType) literal(v any) (string, error) {
+	for _, e := range t.enum {
+		if e.value == v {
+			return e.name, nil
+		}
+	}
+	if t.enum != nil {
+		return "", fmt.Errorf("%v is not an enum value", v)
+	}
+
+	switch t.base {
+	case "string":
+		if s, ok := v.(string); ok {
+			return strconv.Quote(s), nil
+		}
+	case "int":
+		if n, ok := v.(json.Number); ok {
+			if _, err := n.Int64(); err == nil {
+				return n.String(), nil
+			}
+		}
+	case "float64":
+		if n, ok := v.(json.Number); ok {
+			return n.String(), nil
+		}
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+	}
+
+	return "", fmt.Errorf("%v is not a valid %s", v, t.base)
+}
+
+type field struct {
+	name     string
+	jsonName string
+	doc      string
+	typ      goType
+	// array reports whether the field is a slice of typ.
+	array bool
+	// pointer reports whether the field is a pointer to typ.
+	pointer bool
+	// required reports whether the property is required by the schema.
+	required bool
+	// def is the Go literal of the default value, if any.
+	def string
+}
+
+// goType returns the Go type of the field.
+func (f *field) goType() string {
+	switch {
+	case f.array:
+		return "[]" + f.typ.name
+	case f.pointer:
+		return "*" + f.typ.name
+	default:
+		return f.typ.name
+	}
+}
+
+// optional reports whether the field is absent from the map when it is nil.
+// Required fields are null when they are nil, and arrays are empty.
+func (f *field) optional() bool {
+	return !f.required && (f.array || f.pointer || f.typ.base == "any")
+}
+
+type generator struct {
+	typeName string
+	source   string
+	fields   []*field
+	enums    []goType
+}
+
+func (g *generator) field(name string, p map[string]any, required bool) (*field, error) {
+	f := &field{
+		name:     goName(name),
+		jsonName: name,
+		required: required,
+	}
+	f.doc, _ = p["description"].(string)
+
+	if !isExported(f.name) {
+		f.name = "X" + f.name
+	}
+	for _, other := range g.fields {
+		if other.name == f.name {
+			return nil, fmt.Errorf("the Go name %s is already used by property %s", f.name, other.jsonName)
+		}
+	}
+
+	t, nullable := jsonType(p["type"])
+	if t == "array" {
+		items, _ := p["items"].(map[string]any)
+
+		// Arrays that allow null items are []any, as the items can't be pointers.
+		itemType, itemNullable := jsonType(items["type"])
+		if itemNullable {
+			itemType = ""
+		}
+
+		typ, err := g.goType(f.name+"Item", itemType, items["enum"])
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+
+		f.typ = typ
+		f.array = true
+	} else {
+		typ, err := g.goType(f.name, t, p["enum"])
+		if err != nil {
+			return nil, err
+		}
+
+		f.typ = typ
+	}
+
+	if def := p["default"]; def != nil && f.typ.base != "any" {
+		lit, err := f.literal(def)
+		if err != nil {
+			return nil, fmt.Errorf("default: %w", err)
+		}
+		f.def = lit
+	}
+
+	if !f.array && f.typ.base != "any" {
+		f.pointer = nullable || (!required && f.def == "")
+	}
+
+	return f, nil
+}
+
+// literal returns the Go literal of a value of the field.
+func (f *field) literal(v any) (string, error) {
+	if !f.array {
+		return f.typ.literal(v)
+	}
+
+	items, ok := v.([]any)
+	if !ok {
+		return "", fmt.Errorf("%v is not an array", v)
+	}
+
+	lits := make([]string, 0, len(items))
+	for _, item := range items {
+		lit, err := f.typ.literal(item)
+		if err != nil {
+			return "", err
+		}
+		lits = append(lits, lit)
+	}
+
+	return "[]" + f.typ.name + "{" + strings.Join(lits, ", ") + "}", nil
+}
+
+// jsonType returns the JSON type of a "type" keyword, without null, and whether null is allowed.
+// Keywords with several types other than null return an empty type.
+func jsonType(t any) (string, bool) {
+	switch t := t.(type) {
+	case string:
+		return t, t == "null"
+	case []any:
+		var types []string
+		nullable := false
+		for _, v := range t {
+			if v == "null" {
+				nullable = true
+				continue
+			}
+			s, _ := v.(string)
+			types = append(types, s)
+		}
+		if len(types) == 1 {
+			return types[0], nullable
+		}
+		return "", nullable
+	default:
+		return "", false
+	}
+}
+
+// goType returns the Go type of a JSON type. Enums are generated as a named type with constants.
+func (g *generator) goType(name, jsonType string, enum any) (goType, error) {
+	var t goType
+	switch jsonType {
+	case "string":
+		t = goType{name: "string", base: "string"}
+	case "integer":
+		t = goType{name: "int", base: "int"}
+	case "number":
+		t = goType{name: "float64", base: "float64"}
+	case "boolean":
+		t = goType{name: "bool", base: "bool"}
+	case "object", "array":
+		return goType{}, fmt.Errorf("type %s is not supported", jsonType)
+	default:
+		return goType{name: "any", base: "any"}, nil
+	}
+
+	values, _ := enum.([]any)
+	if len(values) == 0 || t.base == "bool" {
+		return t, nil
+	}
+
+	t.name = g.typeName + name
+	t.enum = []enumValue{}
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+
+		if _, err := (goType{name: t.base, base: t.base}).literal(v); err != nil {
+			return goType{}, fmt.Errorf("enum: %w", err)
+		}
+
+		constName := t.name + enumName(v)
+		for i := 2; seen[constName]; i++ {
+			constName = t.name + enumName(v) + strconv.Itoa(i)
+		}
+		seen[constName] = true
+
+		t.enum = append(t.enum, enumValue{name: constName, value: v})
+	}
+	g.enums = append(g.enums, t)
+
+	return t, nil
+}
+
+// enumName returns the suffix of the name of the constant of an enum value.
+func enumName(v any) string {
+	if n, ok := v.(json.Number); ok {
+		return strings.NewReplacer("-", "Minus", ".", "Point", "+", "").Replace(n.String())
+	}
+
+	s, _ := v.(string)
+	if name := goName(s); name != "" {
+		return name
+	}
+
+	return "Empty"
+}
+
+func (g *generator) generate(pkg, description string) []byte {
+	var b bytes.Buffer
+
+	from := ""
+	if g.source != "" {
+		from = " from " + g.source
+	}
+
+	fmt.Fprintf(&b, "// Code generated by tempest-schemagen%s. DO NOT EDIT.\n\n", from)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import (\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\n")
+
+	// The struct.
+	if g.source != "" {
+		fmt.Fprintf(&b, "// %s is generated from %s.\n", g.typeName, g.source)
+	} else {
+		fmt.Fprintf(&b, "// %s is generated from a JSON schema.\n", g.typeName)
+	}
+	if description != "" {
+		fmt.Fprintf(&b, "//\n%s", comment(description, ""))
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", g.typeName)
+	documented := slices.ContainsFunc(g.fields, func(f *field) bool { return f.doc != "" })
+	for i, f := range g.fields {
+		// Documented fields are separated by blank lines, so that gofmt aligns each field on its own.
+		if i > 0 && documented {
+			b.WriteString("\n")
+		}
+		if f.doc != "" {
+			b.WriteString(comment(f.doc, "\t"))
+		}
+
+		tag := f.jsonName
+		if f.optional() {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", f.name, f.goType(), tag)
+	}
+	b.WriteString("}\n\n")
+
+	// The enums.
+	for _, e := range g.enums {
+		fmt.Fprintf(&b, "// %s is a value allowed by the schema.\n", e.name)
+		fmt.Fprintf(&b, "type %s %s\n\n", e.name, e.base)
+		fmt.Fprintf(&b, "// The values of %s.\n", e.name)
+		b.WriteString("const (\n")
+		for _, v := range e.enum {
+			lit, _ := goType{name: e.base, base: e.base}.literal(v.value)
+			fmt.Fprintf(&b, "\t%s %s = %s\n", v.name, e.name, lit)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	recv := strings.ToLower(g.typeName[:1])
+	if recv == "m" || recv == "s" || recv == "i" {
+		recv = "x"
+	}
+
+	// The constructor.
+	fmt.Fprintf(&b, "// New%s returns %s with the default values of the schema.\n", g.typeName, article(g.typeName))
+	fmt.Fprintf(&b, "func New%s() *%s {\n", g.typeName, g.typeName)
+	if slices.ContainsFunc(g.fields, func(f *field) bool { return f.def != "" }) {
+		fmt.Fprintf(&b, "\t%s := &%s{}\n", recv, g.typeName)
+		for _, f := range g.fields {
+			switch {
+			case f.def == "":
+			case f.pointer:
+				fmt.Fprintf(&b, "\tdefault%s := %s(%s)\n", f.name, f.typ.name, f.def)
+				fmt.Fprintf(&b, "\t%s.%s = &default%s\n", recv, f.name, f.name)
+			default:
+				fmt.Fprintf(&b, "\t%s.%s = %s\n", recv, f.name, f.def)
+			}
+		}
+		fmt.Fprintf(&b, "\n\treturn %s\n}\n\n", recv)
+	} else {
+		fmt.Fprintf(&b, "\treturn &%s{}\n}\n\n", g.typeName)
+	}
+
+	// The conversion from a map.
+	// Its local names are fixed rather than derived from the type name, so that they can't clash.
+	fmt.Fprintf(&b, "// %sFromMap converts a map, such as an input or Resource.Properties, to %s.\n", g.typeName, article(g.typeName))
+	fmt.Fprintf(&b, "// Properties that are not in the schema are ignored, and omitted properties have their default values.\n")
+	fmt.Fprintf(&b, "func %sFromMap(m map[string]any) (*%s, error) {\n", g.typeName, g.typeName)
+	b.WriteString("\tdata, err := json.Marshal(m)\n")
+	b.WriteString("\tif err != nil {\n")
+	fmt.Fprintf(&b, "\t\treturn nil, fmt.Errorf(\"marshal %s: %%w\", err)\n", g.typeName)
+	b.WriteString("\t}\n\n")
+	fmt.Fprintf(&b, "\tv := New%s()\n", g.typeName)
+	b.WriteString("\tif err := json.Unmarshal(data, v); err != nil {\n")
+	fmt.Fprintf(&b, "\t\treturn nil, fmt.Errorf(\"unmarshal %s: %%w\", err)\n", g.typeName)
+	b.WriteString("\t}\n\n")
+	b.WriteString("\treturn v, nil\n}\n\n")
+
+	// The conversion to a map.
+	fmt.Fprintf(&b, "// ToMap converts the %s to a map of JSON values, such as Resource.Properties or an action output.\n", g.typeName)
+	fmt.Fprintf(&b, "// Nil pointers and slices are omitted.\n")
+	fmt.Fprintf(&b, "func (%s *%s) ToMap() map[string]any {\n", recv, g.typeName)
+	b.WriteString("\tm := make(map[string]any)\n")
+	for _, f := range g.fields {
+		expr := recv + "." + f.name
+		switch {
+		case f.array:
+			if f.optional() {
+				fmt.Fprintf(&b, "\tif %s != nil {\n", expr)
+			} else {
+				b.WriteString("\t{\n")
+			}
+			fmt.Fprintf(&b, "\t\ts := make([]any, 0, len(%s))\n", expr)
+			fmt.Fprintf(&b, "\t\tfor _, item := range %s {\n", expr)
+			fmt.Fprintf(&b, "\t\t\ts = append(s, %s)\n", f.typ.jsonValue("item"))
+			b.WriteString("\t\t}\n")
+			fmt.Fprintf(&b, "\t\tm[%q] = s\n", f.jsonName)
+			b.WriteString("\t}\n")
+		case f.pointer:
+			fmt.Fprintf(&b, "\tif %s != nil {\n", expr)
+			fmt.Fprintf(&b, "\t\tm[%q] = %s\n", f.jsonName, f.typ.jsonValue("*"+expr))
+			if f.required {
+				b.WriteString("\t} else {\n")
+				fmt.Fprintf(&b, "\t\tm[%q] = nil\n", f.jsonName)
+			}
+			b.WriteString("\t}\n")
+		case f.optional():
+			fmt.Fprintf(&b, "\tif %s != nil {\n", expr)
+			fmt.Fprintf(&b, "\t\tm[%q] = %s\n", f.jsonName, expr)
+			b.WriteString("\t}\n")
+		default:
+			fmt.Fprintf(&b, "\tm[%q] = %s\n", f.jsonName, f.typ.jsonValue(expr))
+		}
+	}
+	b.WriteString("\n\treturn m\n}\n")
+
+	return b.Bytes()
+}
+
+// article returns the name preceded by its indefinite article.
+func article(name string) string {
+	if strings.ContainsRune("AEIOU", rune(name[0])) {
+		return "an " + name
+	}
+
+	return "a " + name
+}
+
+// comment returns the text as a Go comment, each line prefixed with the indentation.
+func comment(text, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+
+	return b.String()
+}
+
+// initialisms are the words written in upper case in Go names.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ARN": true, "CIDR": true, "CPU": true, "DNS": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TLS": true,
+	"TTL": true, "UID": true, "URI": true, "URL": true, "UUID": true, "VPC": true,
+}
+
+// goName returns the exported Go name of a JSON name, such as ExternalID for external_id.
+func goName(s string) string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+
+	return b.String()
+}
+
+func isExported(name string) bool {
+	for i, r := range name {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+
+	return name != ""
+}
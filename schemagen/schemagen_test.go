@@ -0,0 +1,151 @@
+package schemagen
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		schema string
+		opts   Options
+		golden string
+	}{
+		{
+			desc:   "OK - Every Type",
+			schema: "cluster.json",
+			opts:   Options{Package: "clusters", Source: "testdata/cluster.json"},
+			golden: "cluster.go.golden",
+		},
+		{
+			desc:   "OK - Receiver Named b",
+			schema: "bucket.json",
+			opts:   Options{Package: "buckets"},
+			golden: "bucket.go.golden",
+		},
+		{
+			desc:   "OK - No Properties",
+			schema: "empty.json",
+			opts:   Options{Package: "clusters", TypeName: "Empty"},
+			golden: "empty.go.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			schema, err := os.ReadFile(filepath.Join("testdata", tc.schema))
+			require.NoError(t, err)
+
+			src, err := Generate(schema, tc.opts)
+			require.NoError(t, err)
+
+			typeCheck(t, src)
+
+			path := filepath.Join("testdata", tc.golden)
+			if *update {
+				require.NoError(t, os.WriteFile(path, src, 0o644))
+			}
+
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(src))
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		schema string
+		opts   Options
+		err    string
+	}{
+		{
+			desc:   "ERR - No Package",
+			schema: `{"title": "a", "type": "object"}`,
+			err:    "package is required",
+		},
+		{
+			desc:   "ERR - Invalid Schema",
+			schema: `{"type": "object", "properties": {"a": {"type": "object"}}}`,
+			opts:   Options{Package: "p", TypeName: "A"},
+			err:    "parse schema",
+		},
+		{
+			desc:   "ERR - No Type Name",
+			schema: `{"type": "object"}`,
+			opts:   Options{Package: "p"},
+			err:    "type name is required",
+		},
+		{
+			desc:   "ERR - Unexported Type Name",
+			schema: `{"type": "object"}`,
+			opts:   Options{Package: "p", TypeName: "spec"},
+			err:    `type name "spec" is not an exported Go identifier`,
+		},
+		{
+			desc:   "ERR - Duplicate Go Name",
+			schema: `{"type": "object", "properties": {"node_id": {"type": "string"}, "nodeId": {"type": "string"}}}`,
+			opts:   Options{Package: "p", TypeName: "A"},
+			err:    "property node_id: the Go name NodeID is already used by property nodeId",
+		},
+		{
+			desc:   "ERR - Default Not In Enum",
+			schema: `{"type": "object", "properties": {"size": {"type": "string", "enum": ["s"], "default": "m"}}}`,
+			opts:   Options{Package: "p", TypeName: "A"},
+			err:    "property size: default: m is not an enum value",
+		},
+		{
+			desc:   "ERR - Array Of Arrays",
+			schema: `{"type": "object", "properties": {"grid": {"type": "array", "items": {"type": "array"}}}}`,
+			opts:   Options{Package: "p", TypeName: "A"},
+			err:    "property grid: items: type array is not supported",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := Generate([]byte(tc.schema), tc.opts)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"name":        "Name",
+		"external_id": "ExternalID",
+		"vpc-url":     "VPCURL",
+		"nodeCount":   "NodeCount",
+		"HTTPPort":    "HTTPPort",
+		"2fa enabled": "2faEnabled",
+		"__":          "",
+	}
+	for in, want := range testCases {
+		assert.Equal(t, want, goName(in), in)
+	}
+}
+
+// typeCheck fails the test if the generated source does not compile.
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "generated.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: importer.Default()}
+	_, err = conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+	require.NoError(t, err, string(src))
+}